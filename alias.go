@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxAliasDepth stops macros that expand into themselves
+const maxAliasDepth = 10
+
+type aliasEntry struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+	Builtin   bool   `json:"builtin"`
+}
+
+type aliasListResult struct {
+	Aliases []aliasEntry `json:"aliases"`
+}
+
+func (r aliasListResult) renderText(w io.Writer) {
+	for _, alias := range r.Aliases {
+		fmt.Fprintf(w, "%s = %s\n", alias.Name, alias.Expansion)
+	}
+}
+
+func (r aliasListResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, alias := range r.Aliases {
+		rows = append(rows, []string{alias.Name, alias.Expansion, strconv.FormatBool(alias.Builtin)})
+	}
+	return []string{"ALIAS", "EXPANSION", "BUILTIN"}, rows
+}
+
+// lookupCommand finds a command by its name or one of its built-in aliases
+func (s *session) lookupCommand(name string) (cliCommand, bool) {
+	if cmdData, exists := s.commands[name]; exists {
+		return cmdData, true
+	}
+	for _, cmdData := range s.commands {
+		for _, alias := range cmdData.aliases {
+			if alias == name {
+				return cmdData, true
+			}
+		}
+	}
+	return cliCommand{}, false
+}
+
+// expandMacro turns a user alias into the statements it stands for, parsed
+// like a line of input so macros can use ';' and '|' too.
+// $1..$9 are replaced by the arguments and $@ by all of them. a macro
+// without placeholders gets the arguments appended to its last command,
+// like a shell alias
+func expandMacro(name, body string, args []string) ([][][]string, error) {
+	pipelines, err := parseLine(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, stages := range pipelines {
+		for i, stage := range stages {
+			expanded := []string{}
+			for _, word := range stage {
+				switch {
+				case word == "$@":
+					expanded = append(expanded, args...)
+				case len(word) > 1 && word[0] == '$':
+					idx, err := strconv.Atoi(word[1:])
+					if err != nil || idx < 1 {
+						expanded = append(expanded, word)
+						continue
+					}
+					if idx > len(args) {
+						return nil, fmt.Errorf("%s expects at least %d argument(s)", name, idx)
+					}
+					expanded = append(expanded, args[idx-1])
+				default:
+					expanded = append(expanded, word)
+				}
+			}
+			stages[i] = expanded
+		}
+	}
+	if !strings.Contains(body, "$") && len(pipelines) > 0 {
+		stages := pipelines[len(pipelines)-1]
+		last := len(stages) - 1
+		stages[last] = append(stages[last], args...)
+	}
+	return pipelines, nil
+}
+
+func commandAlias(s *session, params ...string) (commandResult, error) {
+	definition := strings.Join(params, " ")
+	if definition == "" {
+		return s.aliasList(""), nil
+	}
+
+	name, body, isDefinition := strings.Cut(definition, "=")
+	name = strings.TrimSpace(name)
+	if !isDefinition {
+		if len(params) > 1 {
+			return nil, fmt.Errorf("usage: alias <name> = <command>[; <command>...]")
+		}
+		result := s.aliasList(name)
+		if len(result.Aliases) == 0 {
+			return nil, fmt.Errorf("no alias named %s", name)
+		}
+		return result, nil
+	}
+
+	body = strings.TrimSpace(body)
+	if name == "" || strings.ContainsAny(name, " ;|$") {
+		return nil, fmt.Errorf("invalid alias name %q", name)
+	}
+	if body == "" {
+		return nil, fmt.Errorf("alias %s needs a command to expand to", name)
+	}
+	if _, exists := s.lookupCommand(name); exists {
+		return nil, fmt.Errorf("%s is already a command", name)
+	}
+	s.aliases[name] = body
+	if err := s.saveConfig(); err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("alias %s = %s", name, body)}, nil
+}
+
+func commandUnalias(s *session, params ...string) (commandResult, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("unalias command requires an alias name")
+	}
+	if _, exists := s.aliases[params[0]]; !exists {
+		return nil, fmt.Errorf("no alias named %s", params[0])
+	}
+	delete(s.aliases, params[0])
+	if err := s.saveConfig(); err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("removed alias %s", params[0])}, nil
+}
+
+// aliasList collects built-in and user aliases, optionally only the one named
+func (s *session) aliasList(only string) aliasListResult {
+	result := aliasListResult{Aliases: []aliasEntry{}}
+	for _, key := range sortedKeys(s.commands) {
+		for _, alias := range s.commands[key].aliases {
+			if only == "" || only == alias {
+				result.Aliases = append(result.Aliases, aliasEntry{Name: alias, Expansion: key, Builtin: true})
+			}
+		}
+	}
+	for _, name := range sortedKeys(s.aliases) {
+		if only == "" || only == name {
+			result.Aliases = append(result.Aliases, aliasEntry{Name: name, Expansion: s.aliases[name]})
+		}
+	}
+	return result
+}
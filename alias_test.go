@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandMacro(t *testing.T) {
+	cases := []struct {
+		body     string
+		args     []string
+		expected [][][]string
+	}{
+		{
+			body:     "map; explore $1",
+			args:     []string{"canalave-city-area"},
+			expected: [][][]string{{{"map"}}, {{"explore", "canalave-city-area"}}},
+		},
+		{
+			body:     "explore",
+			args:     []string{"canalave-city-area"},
+			expected: [][][]string{{{"explore", "canalave-city-area"}}},
+		},
+		{
+			body:     "catch $@; pokedex",
+			args:     []string{"pikachu"},
+			expected: [][][]string{{{"catch", "pikachu"}}, {{"pokedex"}}},
+		},
+		{
+			body:     "explore $1 | catch-all",
+			args:     []string{"canalave-city-area"},
+			expected: [][][]string{{{"explore", "canalave-city-area"}, {"catch-all"}}},
+		},
+		{
+			body:     "map;; map",
+			expected: [][][]string{{{"map"}}, {{"map"}}},
+		},
+	}
+	for _, c := range cases {
+		actual, err := expandMacro("test", c.body, c.args)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.body, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%q: expected %v, got %v", c.body, c.expected, actual)
+		}
+	}
+
+	if _, err := expandMacro("test", "explore $2", []string{"one"}); err == nil {
+		t.Errorf("expected an error for a missing argument")
+	}
+}
+
+func TestAliasCommands(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.configPath = filepath.Join(t.TempDir(), "config.json")
+
+	for _, line := range []string{"alias scout = map; explore $1", "scout canalave-city-area", "e canalave-city-area"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("unexpected errors: %s", errOut.String())
+	}
+	if strings.Count(out.String(), "magikarp") != 2 || !strings.Contains(out.String(), "eterna-city-area") {
+		t.Errorf("expected the macro and the built-in alias to run, got %q", out.String())
+	}
+
+	data, err := os.ReadFile(s.configPath)
+	if err != nil {
+		t.Fatalf("expected the alias to be saved: %v", err)
+	}
+	if !strings.Contains(string(data), `"scout": "map; explore $1"`) {
+		t.Errorf("unexpected config file contents: %s", data)
+	}
+
+	reloaded, _, _ := newTestSession(t)
+	reloaded.configPath = s.configPath
+	if err := reloaded.loadConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reloaded.aliases["scout"] != "map; explore $1" {
+		t.Errorf("expected the alias to be loaded, got %v", reloaded.aliases)
+	}
+
+	if err := s.runLine("alias map = mapb"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "already a command") {
+		t.Errorf("expected aliases to not shadow commands, got %q", errOut.String())
+	}
+}
+
+func TestAliasRecursionIsBounded(t *testing.T) {
+	s, _, errOut := newTestSession(t)
+	s.aliases["loop"] = "loop"
+	if err := s.runLine("loop"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "expands too deeply") {
+		t.Errorf("expected a recursion error, got %q", errOut.String())
+	}
+}
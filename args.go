@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// parseFlags splits a command's parameters into positional arguments and
+// --flags. flags in valueFlags take a value, either as the next parameter
+// or after an '=', flags in switchFlags are set to "true" when present
+func parseFlags(params []string, valueFlags, switchFlags []string) ([]string, map[string]string, error) {
+	args := []string{}
+	flags := map[string]string{}
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if !strings.HasPrefix(param, "--") {
+			args = append(args, param)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(param, "--"), "=")
+		name = strings.ToLower(name)
+		switch {
+		case slices.Contains(switchFlags, name):
+			if hasValue {
+				return nil, nil, fmt.Errorf("--%s does not take a value", name)
+			}
+			flags[name] = "true"
+		case slices.Contains(valueFlags, name):
+			if !hasValue {
+				if i+1 >= len(params) {
+					return nil, nil, fmt.Errorf("--%s requires a value", name)
+				}
+				i++
+				value = params[i]
+			}
+			flags[name] = value
+		default:
+			return nil, nil, fmt.Errorf("unknown flag --%s", name)
+		}
+	}
+	return args, flags, nil
+}
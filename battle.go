@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+const (
+	// maxMoves is how many moves a pokemon can know at once
+	maxMoves = 4
+	// maxTurns ends a battle that neither side can win, such as two
+	// pokemon that only know splash
+	maxTurns = 100
+	// criticalChance is the one in n chance of a critical hit
+	criticalChance = 24
+)
+
+type pokeMove struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Accuracy    *int              `json:"accuracy"`
+	Power       *int              `json:"power"`
+	PP          int               `json:"pp"`
+	Priority    int               `json:"priority"`
+	Type        pokeNamedResource `json:"type"`
+	DamageClass pokeNamedResource `json:"damage_class"`
+}
+
+func (c *pokeClient) getMove(name string) (pokeMove, error) {
+	var move pokeMove
+	err := c.get(c.resourceUrl("move", name), &move)
+	return move, err
+}
+
+// battleStat is a stat other than hp at a level, the generation iii
+// formula without individual values, effort values or nature
+func battleStat(base, level int) int {
+	return 2*base*level/100 + 5
+}
+
+// baseDamage is the mainline damage formula before any modifiers
+func baseDamage(level, power, attack, defense int) int {
+	return (2*level/5+2)*power*attack/defense/50 + 2
+}
+
+// combatant is one side of a battle
+type combatant struct {
+	name      string
+	level     int
+	types     []string
+	hp        int
+	maxHP     int
+	attack    int
+	defense   int
+	spAttack  int
+	spDefense int
+	speed     int
+	moves     []pokeMove
+}
+
+// newCombatant sets a pokemon up for battle at a level, knowing the last
+// moves it learned by levelling up, the way wild pokemon do
+func (s *session) newCombatant(pokemon pokePokemon, level int) (*combatant, error) {
+	fighter := &combatant{
+		name:      pokemon.Name,
+		level:     level,
+		maxHP:     maxHP(pokemon.baseStat("hp"), level),
+		attack:    battleStat(pokemon.baseStat("attack"), level),
+		defense:   battleStat(pokemon.baseStat("defense"), level),
+		spAttack:  battleStat(pokemon.baseStat("special-attack"), level),
+		spDefense: battleStat(pokemon.baseStat("special-defense"), level),
+		speed:     battleStat(pokemon.baseStat("speed"), level),
+	}
+	fighter.hp = fighter.maxHP
+	for _, pokemonType := range pokemon.Types {
+		fighter.types = append(fighter.types, pokemonType.Type.Name)
+	}
+
+	versionGroup := s.versionGroup
+	if versionGroup == "" {
+		versionGroup = latestVersionGroup(pokemon)
+	}
+	known := []string{}
+	for _, move := range learnset(pokemon, versionGroup, "level-up") {
+		if move.Level <= level && !slices.Contains(known, move.Name) {
+			known = append(known, move.Name)
+		}
+	}
+	known = known[max(0, len(known)-maxMoves):]
+	if len(known) == 0 {
+		known = []string{"struggle"}
+	}
+	for _, name := range known {
+		move, err := s.client.getMove(name)
+		if err != nil {
+			return nil, err
+		}
+		fighter.moves = append(fighter.moves, move)
+	}
+	return fighter, nil
+}
+
+func (c *combatant) fainted() bool {
+	return c.hp <= 0
+}
+
+// chooseMove picks a random move, as wild pokemon do
+func (s *session) chooseMove(fighter *combatant) pokeMove {
+	return fighter.moves[s.rng.Intn(len(fighter.moves))]
+}
+
+type battleTurn struct {
+	Attacker string `json:"attacker"`
+	Move     string `json:"move"`
+	Missed   bool   `json:"missed,omitempty"`
+	Critical bool   `json:"critical,omitempty"`
+	Immune   bool   `json:"immune,omitempty"`
+	Damage   int    `json:"damage"`
+	Target   string `json:"target"`
+	TargetHP int    `json:"target_hp"`
+	// Effectiveness is the type multiplier of a damaging move that landed
+	Effectiveness float64 `json:"effectiveness,omitempty"`
+}
+
+// attack runs one move from attacker against defender
+func (s *session) attack(attacker, defender *combatant, move pokeMove) (battleTurn, error) {
+	turn := battleTurn{Attacker: attacker.name, Move: move.Name, Target: defender.name, TargetHP: defender.hp}
+
+	if move.Accuracy != nil && s.rng.Intn(100) >= *move.Accuracy {
+		turn.Missed = true
+		return turn, nil
+	}
+	if move.Power == nil || move.DamageClass.Name == "status" {
+		return turn, nil
+	}
+	multiplier, err := s.effectiveness(move.Type.Name, defender.types)
+	if err != nil {
+		return battleTurn{}, err
+	}
+	turn.Effectiveness = multiplier
+	if multiplier == 0 {
+		turn.Immune = true
+		return turn, nil
+	}
+
+	attack, defense := attacker.attack, defender.defense
+	if move.DamageClass.Name == "special" {
+		attack, defense = attacker.spAttack, defender.spDefense
+	}
+	damage := float64(baseDamage(attacker.level, *move.Power, attack, defense))
+	if s.rng.Intn(criticalChance) == 0 {
+		turn.Critical = true
+		damage *= 1.5
+	}
+	// the random factor is between 85% and 100%
+	damage = damage * float64(85+s.rng.Intn(16)) / 100
+	if slices.Contains(attacker.types, move.Type.Name) {
+		damage *= 1.5
+	}
+	damage *= multiplier
+	turn.Damage = max(1, int(damage))
+	defender.hp = max(0, defender.hp-turn.Damage)
+	turn.TargetHP = defender.hp
+	return turn, nil
+}
+
+// goesFirst decides the turn order by move priority and then speed, with
+// a coin toss for ties
+func (s *session) goesFirst(a, b *combatant, moveA, moveB pokeMove) bool {
+	if moveA.Priority != moveB.Priority {
+		return moveA.Priority > moveB.Priority
+	}
+	if a.speed != b.speed {
+		return a.speed > b.speed
+	}
+	return s.rng.Intn(2) == 0
+}
+
+type battleResult struct {
+	Pokemon  string       `json:"pokemon"`
+	Opponent string       `json:"opponent"`
+	Level    int          `json:"opponent_level"`
+	Winner   string       `json:"winner"`
+	Turns    []battleTurn `json:"turns"`
+	// Gain is the experience the trainer's pokemon earned by winning
+	Gain *experienceGain `json:"gain,omitempty"`
+	// Prize is the evolution item given for every few battles won
+	Prize string `json:"prize,omitempty"`
+}
+
+func (r battleResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "%s vs %s (level %d)\n", r.Pokemon, r.Opponent, r.Level)
+	for _, turn := range r.Turns {
+		fmt.Fprintf(w, "%s used %s!", turn.Attacker, turn.Move)
+		switch {
+		case turn.Missed:
+			fmt.Fprint(w, " It missed!")
+		case turn.Immune:
+			fmt.Fprintf(w, " It doesn't affect %s...", turn.Target)
+		case turn.Damage == 0:
+			fmt.Fprint(w, " Nothing happened!")
+		default:
+			if turn.Critical {
+				fmt.Fprint(w, " A critical hit!")
+			}
+			switch effectivenessText(turn.Effectiveness) {
+			case "super effective":
+				fmt.Fprint(w, " It's super effective!")
+			case "not very effective":
+				fmt.Fprint(w, " It's not very effective...")
+			}
+			fmt.Fprintf(w, " %s took %d damage (%d hp left)", turn.Target, turn.Damage, turn.TargetHP)
+		}
+		fmt.Fprintln(w)
+	}
+	if r.Winner == "" {
+		fmt.Fprintln(w, "Neither pokemon could win, the battle is a draw")
+		return
+	}
+	fmt.Fprintf(w, "%s won the battle!\n", r.Winner)
+	if r.Gain != nil {
+		r.Gain.renderText(w)
+	}
+	if r.Prize != "" {
+		fmt.Fprintf(w, "You won a %s as a prize! (see evolve --item)\n", r.Prize)
+	}
+}
+
+func (r battleResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for i, turn := range r.Turns {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			turn.Attacker,
+			turn.Move,
+			strconv.FormatBool(turn.Missed),
+			strconv.FormatBool(turn.Critical),
+			strconv.Itoa(turn.Damage),
+			turn.Target,
+			strconv.Itoa(turn.TargetHP),
+		})
+	}
+	return []string{"TURN", "ATTACKER", "MOVE", "MISSED", "CRITICAL", "DAMAGE", "TARGET", "TARGET HP"}, rows
+}
+
+// fight runs turns until one side faints or the turn limit is reached
+func (s *session) fight(mine, theirs *combatant) ([]battleTurn, error) {
+	turns := []battleTurn{}
+	for round := 0; round < maxTurns && !mine.fainted() && !theirs.fainted(); round++ {
+		myMove, theirMove := s.chooseMove(mine), s.chooseMove(theirs)
+		first, second := mine, theirs
+		firstMove, secondMove := myMove, theirMove
+		if !s.goesFirst(mine, theirs, myMove, theirMove) {
+			first, second = theirs, mine
+			firstMove, secondMove = theirMove, myMove
+		}
+		turn, err := s.attack(first, second, firstMove)
+		if err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn)
+		if second.fainted() {
+			break
+		}
+		turn, err = s.attack(second, first, secondMove)
+		if err != nil {
+			return nil, err
+		}
+		turns = append(turns, turn)
+	}
+	return turns, nil
+}
+
+func commandBattle(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"level"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("battle command takes one of your pokemon (id or name) and an opponent (the wild pokemon when none is given)")
+	}
+	caught, err := s.findCaught(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	opponentName := ""
+	if len(args) == 2 {
+		opponentName = args[1]
+	} else if s.wild != nil {
+		opponentName = s.wild.Name
+	} else {
+		return nil, fmt.Errorf("there is no wild pokemon to battle, encounter one or name an opponent")
+	}
+	isWild := s.wild != nil && s.wild.Name == opponentName
+
+	species, rate, err := s.growthRate(caught.Name)
+	if err != nil {
+		return nil, err
+	}
+	caught.normalize(species, rate)
+	opponentLevel := caught.Level
+	if isWild {
+		opponentLevel = s.wild.Level
+	}
+	if value, ok := flags["level"]; ok {
+		opponentLevel, err = strconv.Atoi(value)
+		if err != nil || opponentLevel < 1 || opponentLevel > maxLevel {
+			return nil, fmt.Errorf("level must be a number from 1 to %d", maxLevel)
+		}
+	}
+
+	myPokemon, err := s.client.getPokemon(caught.Name)
+	if err != nil {
+		return nil, err
+	}
+	opponent, err := s.client.getPokemon(opponentName)
+	if err != nil {
+		return nil, err
+	}
+	mine, err := s.newCombatant(myPokemon, caught.Level)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := s.newCombatant(opponent, opponentLevel)
+	if err != nil {
+		return nil, err
+	}
+	s.pokedex.see(opponent.Name, s.now())
+
+	turns, err := s.fight(mine, theirs)
+	if err != nil {
+		return nil, err
+	}
+	result := battleResult{
+		Pokemon:  mine.name,
+		Opponent: theirs.name,
+		Level:    theirs.level,
+		Turns:    turns,
+	}
+	switch {
+	case theirs.fainted():
+		result.Winner = mine.name
+		s.stats.BattlesWon++
+		if isWild {
+			s.wild = nil
+		}
+		gain, err := s.gainExperience(caught.ID, experienceYield(opponent.BaseExperience, theirs.level))
+		if err != nil {
+			return nil, err
+		}
+		result.Gain = &gain
+		if s.stats.BattlesWon%battlesPerPrize == 0 {
+			if result.Prize, err = s.prizeItem(caught.Name); err != nil {
+				return nil, err
+			}
+			s.inventory[result.Prize]++
+		}
+	case mine.fainted():
+		result.Winner = theirs.name
+		s.stats.BattlesLost++
+	}
+	return result, nil
+}
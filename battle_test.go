@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBaseDamage(t *testing.T) {
+	cases := []struct {
+		level, power, attack, defense, expected int
+	}{
+		{10, 40, 20, 20, 6},
+		{50, 90, 100, 80, 51},
+		{100, 150, 300, 100, 380},
+	}
+	for _, c := range cases {
+		if damage := baseDamage(c.level, c.power, c.attack, c.defense); damage != c.expected {
+			t.Errorf("level %d power %d %d/%d: expected %d, got %d", c.level, c.power, c.attack, c.defense, c.expected, damage)
+		}
+	}
+}
+
+func TestGoesFirst(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	slow := &combatant{speed: 10}
+	fast := &combatant{speed: 50}
+	normal := pokeMove{Name: "tackle"}
+	quick := pokeMove{Name: "quick-attack", Priority: 1}
+	if !s.goesFirst(fast, slow, normal, normal) {
+		t.Errorf("expected the faster pokemon to go first")
+	}
+	if !s.goesFirst(slow, fast, quick, normal) {
+		t.Errorf("expected a priority move to go first")
+	}
+}
+
+func TestBattle(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	result, err := commandBattle(s, "pikachu", "magikarp", "--level", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	battle := result.(battleResult)
+	if battle.Winner != "pikachu" {
+		t.Errorf("expected pikachu to win against a magikarp that only knows splash, got %+v", battle)
+	}
+	for _, turn := range battle.Turns {
+		if turn.Attacker == "magikarp" && turn.Damage != 0 {
+			t.Errorf("expected splash to do nothing, got %+v", turn)
+		}
+		if turn.Attacker == "pikachu" && turn.Move != "thunder-shock" {
+			t.Errorf("expected a level 10 pikachu to only know thunder-shock, got %s", turn.Move)
+		}
+	}
+	// magikarp yields 40*5/7 = 28 exp
+	if battle.Gain == nil || battle.Gain.Experience != 28 || s.caught[1].Experience != 1028 {
+		t.Errorf("expected pikachu to gain 28 exp, got %+v and %d exp", battle.Gain, s.caught[1].Experience)
+	}
+	if s.stats.BattlesWon != 1 {
+		t.Errorf("expected the win to be counted, got %+v", s.stats)
+	}
+
+	// every fifth win is worth an item pikachu evolves with
+	s.stats.BattlesWon = battlesPerPrize - 1
+	result, err = commandBattle(s, "pikachu", "magikarp", "--level", "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prize := result.(battleResult).Prize; prize != "thunder-stone" || s.inventory["thunder-stone"] != 1 {
+		t.Errorf("expected a thunder-stone prize, got %q and %v", prize, s.inventory)
+	}
+
+	if _, err := commandBattle(s, "pikachu"); err == nil {
+		t.Errorf("expected an error without a wild pokemon or opponent")
+	}
+	if _, err := commandBattle(s, "magikarp", "pikachu"); err == nil {
+		t.Errorf("expected an error battling with a pokemon that was not caught")
+	}
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultWildLevel is the level assumed for a pokemon caught by name rather
+// than met in an encounter
+const defaultWildLevel = 10
+
+// statusBonus is the capture multiplier for a status condition
+var statusBonus = map[string]float64{
+	"none":     1,
+	"sleep":    2,
+	"freeze":   2,
+	"paralyze": 1.5,
+	"poison":   1.5,
+	"burn":     1.5,
+}
+
+// catchOptions describes the ball thrown and the state of the wild pokemon
+// it is thrown at. a level of 0 means the level of the wild encounter, or
+// defaultWildLevel
+type catchOptions struct {
+	level     int
+	hpPercent int
+	status    string
+	ball      ballType
+}
+
+func defaultCatchOptions() catchOptions {
+	return catchOptions{
+		level:     0,
+		hpPercent: 100,
+		status:    "none",
+		ball:      ballTypes[0],
+	}
+}
+
+// parseCatchOptions reads the --ball, --level, --hp and --status flags
+// shared by catch and catch-all
+func parseCatchOptions(flags map[string]string) (catchOptions, error) {
+	opts := defaultCatchOptions()
+	if value, ok := flags["ball"]; ok {
+		ball, err := findBall(value)
+		if err != nil {
+			return opts, err
+		}
+		opts.ball = ball
+	}
+	if value, ok := flags["level"]; ok {
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 1 || level > 100 {
+			return opts, fmt.Errorf("--level must be between 1 and 100")
+		}
+		opts.level = level
+	}
+	if value, ok := flags["hp"]; ok {
+		percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || percent < 1 || percent > 100 {
+			return opts, fmt.Errorf("--hp must be a percentage between 1 and 100")
+		}
+		opts.hpPercent = percent
+	}
+	if value, ok := flags["status"]; ok {
+		if _, known := statusBonus[value]; !known {
+			return opts, fmt.Errorf("unknown status %q (expected %s)", value, strings.Join(sortedKeys(statusBonus), ", "))
+		}
+		opts.status = value
+	}
+	return opts, nil
+}
+
+// maxHP is the hp stat of a pokemon at a level, without ivs or evs
+func maxHP(baseHP, level int) int {
+	return (2*baseHP*level)/100 + level + 10
+}
+
+// catchValue is the modified catch rate "a" from the generation iii/iv
+// capture formula. a value of 255 or more is a guaranteed catch
+func catchValue(captureRate, hpMax, hpCur int, ball, status float64) float64 {
+	a := float64(3*hpMax-2*hpCur) * float64(captureRate) * ball / float64(3*hpMax)
+	return math.Max(1, a*status)
+}
+
+// shakeThreshold is "b", each of the four shake checks passes when a random
+// number below 65536 is less than it
+func shakeThreshold(a float64) int {
+	if a >= 255 {
+		return 65536
+	}
+	return int(1048560 / math.Sqrt(math.Sqrt(16711680/a)))
+}
+
+// catchProbability is the chance that all four shake checks pass
+func catchProbability(a float64) float64 {
+	return math.Min(1, math.Pow(float64(shakeThreshold(a))/65536, 4))
+}
+
+type catchResult struct {
+	ID      int     `json:"id,omitempty"`
+	Pokemon string  `json:"pokemon"`
+	Ball    string  `json:"ball"`
+	Caught  bool    `json:"caught"`
+	Shakes  int     `json:"shakes"`
+	Chance  float64 `json:"chance"`
+	Shiny   bool    `json:"shiny,omitempty"`
+}
+
+func (r catchResult) renderText(w io.Writer) {
+	if r.Caught && r.Shiny {
+		fmt.Fprintf(w, "%s was caught! It's shiny!\n", r.Pokemon)
+	} else if r.Caught {
+		fmt.Fprintf(w, "%s was caught!\n", r.Pokemon)
+	} else {
+		fmt.Fprintf(w, "%s escaped!\n", r.Pokemon)
+	}
+}
+
+func (r catchResult) pipeValues() []string {
+	if r.Caught {
+		return []string{r.Pokemon}
+	}
+	return []string{}
+}
+
+func (r catchResult) tableRows() ([]string, [][]string) {
+	return []string{"POKEMON", "BALL", "CAUGHT", "SHAKES", "CHANCE"}, [][]string{r.row()}
+}
+
+func (r catchResult) row() []string {
+	return []string{r.Pokemon, r.Ball, strconv.FormatBool(r.Caught), strconv.Itoa(r.Shakes), fmt.Sprintf("%.1f%%", r.Chance*100)}
+}
+
+// catchFailure is a pokemon catch-all could not throw a ball at
+type catchFailure struct {
+	Pokemon string `json:"pokemon"`
+	Error   string `json:"error"`
+}
+
+// catchAllResult lists every throw, the pokemon that could not be thrown
+// at, and the pokemon left over when the balls ran out
+type catchAllResult struct {
+	Results []catchResult  `json:"results"`
+	Failed  []catchFailure `json:"failed,omitempty"`
+	Skipped []string       `json:"skipped,omitempty"`
+}
+
+func (r catchAllResult) renderText(w io.Writer) {
+	for _, res := range r.Results {
+		res.renderText(w)
+	}
+	for _, failure := range r.Failed {
+		fmt.Fprintf(w, "Could not catch %s: %s\n", failure.Pokemon, failure.Error)
+	}
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(w, "Out of balls, skipped: %s\n", strings.Join(r.Skipped, ", "))
+	}
+}
+
+func (r catchAllResult) pipeValues() []string {
+	caught := []string{}
+	for _, res := range r.Results {
+		caught = append(caught, res.pipeValues()...)
+	}
+	return caught
+}
+
+func (r catchAllResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, res := range r.Results {
+		rows = append(rows, res.row())
+	}
+	for _, failure := range r.Failed {
+		rows = append(rows, []string{failure.Pokemon, "", "error: " + failure.Error, "", ""})
+	}
+	return []string{"POKEMON", "BALL", "CAUGHT", "SHAKES", "CHANCE"}, rows
+}
+
+var catchValueFlags = []string{"ball", "level", "hp", "status"}
+
+func commandCatch(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, catchValueFlags, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 && s.wild != nil {
+		args = []string{s.wild.Name}
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("catch command requires a pokemon id or name, or a wild encounter")
+	}
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("catch command only takes one pokemon")
+	}
+
+	opts, err := parseCatchOptions(flags)
+	if err != nil {
+		return nil, err
+	}
+	return s.throwBall(args[0], opts)
+}
+
+func commandCatchAll(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, catchValueFlags, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("catch-all command requires at least one pokemon id or name")
+	}
+	opts, err := parseCatchOptions(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	// a pokemon that can not be thrown at does not stop the rest, the balls
+	// already thrown are used up either way
+	result := catchAllResult{Results: []catchResult{}}
+	var firstErr error
+	for i, name := range args {
+		if s.inventory[opts.ball.item] <= 0 {
+			result.Skipped = args[i:]
+			break
+		}
+		caught, err := s.throwBall(name, opts)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Failed = append(result.Failed, catchFailure{Pokemon: name, Error: err.Error()})
+			continue
+		}
+		result.Results = append(result.Results, caught)
+	}
+	if len(result.Results) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// throwBall makes one attempt at catching a pokemon using the species
+// capture rate, shaking the ball once for every shake check that passes.
+// the ball is used up whether or not the pokemon is caught
+func (s *session) throwBall(name string, opts catchOptions) (catchResult, error) {
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return catchResult{}, err
+	}
+	if err := s.checkCatchable(pokemon.Name); err != nil {
+		return catchResult{}, err
+	}
+	species, err := s.client.getSpecies(pokemon.Species.Name)
+	if err != nil {
+		return catchResult{}, err
+	}
+	rate, err := s.client.getGrowthRate(species.GrowthRate.Name)
+	if err != nil {
+		return catchResult{}, err
+	}
+	if !s.hasRoom() {
+		return catchResult{}, fmt.Errorf("your party and boxes are full, release some pokemon first")
+	}
+	if err := s.useBall(opts.ball); err != nil {
+		return catchResult{}, err
+	}
+
+	level := opts.level
+	isWild := s.wild != nil && s.wild.Name == pokemon.Name
+	if level == 0 && isWild {
+		level = s.wild.Level
+	}
+	if level == 0 {
+		level = defaultWildLevel
+	}
+	hpMax := maxHP(pokemon.baseStat("hp"), level)
+	hpCur := max(1, hpMax*opts.hpPercent/100)
+	a := catchValue(species.CaptureRate, hpMax, hpCur, opts.ball.modifier, statusBonus[opts.status])
+	threshold := shakeThreshold(a)
+
+	s.status("Throwing a %s at %s...\n", opts.ball.name, pokemon.Name)
+	shakes := 0
+	for shakes < 4 && s.rng.Intn(65536) < threshold {
+		shakes++
+		time.Sleep(s.shakeDelay)
+		// the fourth check passing is the ball clicking shut, not a shake
+		if shakes < 4 {
+			s.status("...shake\n")
+		}
+	}
+
+	caught := shakes == 4
+	id := 0
+	shiny := false
+	s.stats.BallsThrown++
+	s.pokedex.recordAttempt(pokemon.Name, caught, s.now())
+	if caught {
+		// a wild pokemon was already shiny or not when it appeared, one
+		// caught by name is rolled for in the version being played
+		if isWild {
+			shiny = s.wild.Shiny
+			s.wild = nil
+		} else if shiny, err = s.rollShiny(s.version); err != nil {
+			return catchResult{}, err
+		}
+		s.stats.PokemonCaught++
+		if err := s.rewardLead(pokemon, level); err != nil {
+			return catchResult{}, err
+		}
+		individual := s.individual(species)
+		individual.Name = pokemon.Name
+		individual.CaughtAt = s.now()
+		individual.CaughtIn = s.location
+		individual.Level = level
+		individual.Experience = rate.experienceAt(level)
+		individual.Friendship = species.BaseHappiness
+		individual.Shiny = shiny
+		stored, box, err := s.store(individual)
+		if err != nil {
+			return catchResult{}, err
+		}
+		id = stored.ID
+		if shiny {
+			s.pokedex.registerShiny(pokemon.Name)
+		}
+		if box > 0 {
+			s.status("Your party is full, %s was sent to box %d\n", pokemon.Name, box)
+		}
+	}
+
+	return catchResult{
+		ID:      id,
+		Pokemon: pokemon.Name,
+		Ball:    opts.ball.item,
+		Caught:  caught,
+		Shakes:  min(shakes, 3),
+		Chance:  catchProbability(a),
+		Shiny:   shiny,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCatchProbability(t *testing.T) {
+	cases := []struct {
+		name     string
+		rate     int
+		hpCur    int
+		ball     float64
+		status   float64
+		expected float64
+	}{
+		{name: "magikarp at full hp", rate: 255, hpCur: 30, ball: 1, status: 1, expected: 0.333},
+		{name: "pikachu at full hp", rate: 190, hpCur: 30, ball: 1, status: 1, expected: 0.248},
+		{name: "pikachu at 1 hp", rate: 190, hpCur: 1, ball: 1, status: 1, expected: 0.728},
+		{name: "pikachu asleep", rate: 190, hpCur: 30, ball: 1, status: 2, expected: 0.497},
+		{name: "mewtwo at full hp", rate: 3, hpCur: 30, ball: 1, status: 1, expected: 0.004},
+		{name: "guaranteed", rate: 255, hpCur: 30, ball: 255, status: 1, expected: 1},
+	}
+	for _, c := range cases {
+		a := catchValue(c.rate, 30, c.hpCur, c.ball, c.status)
+		actual := catchProbability(a)
+		if math.Abs(actual-c.expected) > 0.001 {
+			t.Errorf("%s: expected %.3f, got %.3f", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestMaxHP(t *testing.T) {
+	if hp := maxHP(35, 10); hp != 27 {
+		t.Errorf("expected a level 10 pikachu to have 27 hp, got %d", hp)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	args, flags, err := parseFlags([]string{"pikachu", "--hp", "50%", "--status=sleep", "--details"}, []string{"hp", "status"}, []string{"details"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(args, []string{"pikachu"}) {
+		t.Errorf("unexpected args %v", args)
+	}
+	expected := map[string]string{"hp": "50%", "status": "sleep", "details": "true"}
+	if !reflect.DeepEqual(flags, expected) {
+		t.Errorf("expected %v, got %v", expected, flags)
+	}
+
+	for _, params := range [][]string{{"--bogus"}, {"--hp"}, {"--details=yes"}} {
+		if _, _, err := parseFlags(params, []string{"hp"}, []string{"details"}); err == nil {
+			t.Errorf("%v: expected an error", params)
+		}
+	}
+}
+
+func TestCatchCommandOptions(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	if err := s.runLine("catch pikachu --hp 200"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "--hp must be a percentage") {
+		t.Errorf("expected an hp error, got %q", errOut.String())
+	}
+
+	s.output = formatJSON
+	if err := s.runLine("catch magikarp --hp 10% --status sleep --level 30"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"chance": 1`) {
+		t.Errorf("expected the catch chance in the output, got %q", out.String())
+	}
+}
+
+func TestBallInventory(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.output = formatJSON
+	if err := s.runLine("catch tentacool --ball master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"caught": true`) || !strings.Contains(out.String(), `"ball": "master-ball"`) {
+		t.Errorf("expected the master ball to always catch, got %q", out.String())
+	}
+	if s.inventory["master-ball"] != 0 {
+		t.Errorf("expected the master ball to be used up, have %d", s.inventory["master-ball"])
+	}
+
+	if err := s.runLine("catch tentacool --ball master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "you have no Master Balls left") {
+		t.Errorf("expected running out of balls to block the catch, got %q", errOut.String())
+	}
+	if entry, _ := s.pokedex.entry("tentacool"); entry.Attempts != 1 {
+		t.Errorf("expected a blocked catch to not count as an attempt")
+	}
+
+	s.inventory["ultra-ball"] = 2
+	out.Reset()
+	if err := s.runLine("explore canalave-city-area | catch-all --ball ultra"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"skipped": [`+"\n"+`    "wingull",`) {
+		t.Errorf("expected the pokemon after the last ultra ball to be skipped, got %q", out.String())
+	}
+
+	// a throw that fails keeps the ones before and after it
+	s.inventory["ultra-ball"] = 5
+	result, err := commandCatchAll(s, "magikarp", "missingno", "tentacool", "--ball", "ultra")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	catchAll := result.(catchAllResult)
+	if len(catchAll.Results) != 2 || len(catchAll.Failed) != 1 || catchAll.Failed[0].Pokemon != "missingno" {
+		t.Errorf("expected two throws and missingno to fail, got %+v", catchAll)
+	}
+	if _, err := commandCatchAll(s, "missingno"); err == nil {
+		t.Errorf("expected an error when no ball could be thrown")
+	}
+
+	if _, err := findBall("premier"); err == nil {
+		t.Errorf("expected an error for an unknown ball")
+	}
+}
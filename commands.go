@@ -0,0 +1,396 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type cliCommand struct {
+	name        string
+	description string
+	aliases     []string
+	callback    func(s *session, params ...string) (commandResult, error)
+	// keepCase passes the arguments as typed, for commands taking file
+	// paths or text to show. they lowercase what they look up themselves
+	keepCase bool
+}
+
+// defaultCommands builds the command table for a new session
+func defaultCommands() map[string]cliCommand {
+	return map[string]cliCommand{
+		"exit": {
+			name:        "exit",
+			description: "Exit the pokedex",
+			aliases:     []string{"q"},
+			callback:    commandExit,
+		},
+		"help": {
+			name:        "help",
+			description: "Displays a help message",
+			aliases:     []string{"?"},
+			callback:    commandHelp,
+		},
+		"map": {
+			name:        "map",
+			description: "Displays the next 20 areas in the pokedex",
+			aliases:     []string{"m"},
+			callback:    commandMap,
+		},
+		"mapb": {
+			name:        "mapb",
+			description: "Displays the previous 20 areas in the pokedex",
+			aliases:     []string{"b"},
+			callback:    commandMapb,
+		},
+		"explore": {
+			name:        "explore <area id or name> [--details]",
+			description: "Travels to an area and displays the pokemon that live there, with --details how and when they can be met",
+			aliases:     []string{"e"},
+			callback:    commandExplore,
+		},
+		"travel": {
+			name:        "travel [<area id or name>]",
+			description: "Travels to an area without exploring it, or shows where you are",
+			callback:    commandTravel,
+		},
+		"encounter": {
+			name:        "encounter [--method walk|surf|old-rod...] [--version name]",
+			description: "Looks for a wild pokemon in the current area, at the rates and levels of the game",
+			aliases:     []string{"walk"},
+			callback:    commandEncounter,
+		},
+		"catch": {
+			name:        "catch [<pokemon id or name>] [--ball poke|great|ultra|master] [--level n] [--hp percent] [--status name]",
+			description: "Attempts to catch a pokemon, the wild one you encountered when none is given",
+			aliases:     []string{"c"},
+			callback:    commandCatch,
+		},
+		"catch-all": {
+			name:        "catch-all <pokemon>...",
+			description: "Attempts to catch every pokemon given, e.g. explore <area> | catch-all",
+			callback:    commandCatchAll,
+		},
+		"inspect": {
+			name:        "inspect <pokemon> [--sprite]",
+			description: "Displays the stats of a caught pokemon, or what is known about a seen one",
+			aliases:     []string{"i"},
+			callback:    commandInspect,
+		},
+		"species": {
+			name:        "species <pokemon>",
+			description: "Displays a species' genus, pokedex entries, habitat, breeding and other details",
+			callback:    commandSpecies,
+		},
+		"sprite": {
+			name:        "sprite <pokemon> [--shiny] [--back] [--gen iii] [--colors truecolor|256|ascii]",
+			description: "Draws a pokemon's sprite in the terminal",
+			callback:    commandSprite,
+		},
+		"cry": {
+			name:        "cry <pokemon> [--legacy] [--out file] [--play]",
+			description: "Saves a pokemon's cry to an ogg file, or plays it",
+			callback:    commandCry,
+			keepCase:    true,
+		},
+		"quiz": {
+			name:        "quiz [--from caught|area] [--gen iii] [--hints] [--time seconds] | quiz hint|skip|score",
+			description: "Starts a round of who's that pokemon",
+			callback:    commandQuiz,
+		},
+		"guess": {
+			name:        "guess <pokemon>",
+			description: "Answers the quiz",
+			callback:    commandGuess,
+		},
+		"pokedex": {
+			name:        "pokedex [caught|seen]",
+			description: "Displays the pokemon you have seen and caught",
+			aliases:     []string{"p"},
+			callback:    commandPokedex,
+		},
+		"alias": {
+			name:        "alias [<name> = <command>[; <command>...]]",
+			description: "Lists aliases, or defines a macro ($1, $2... and $@ are replaced by its arguments)",
+			callback:    commandAlias,
+		},
+		"unalias": {
+			name:        "unalias <name>",
+			description: "Removes a user defined alias",
+			callback:    commandUnalias,
+		},
+		"inventory": {
+			name:        "inventory",
+			description: "Displays how many of each ball and item you have",
+			callback:    commandInventory,
+		},
+		"save": {
+			name:        "save",
+			description: "Saves your pokedex, pokemon, inventory and settings",
+			callback:    commandSave,
+		},
+		"load": {
+			name:        "load",
+			description: "Loads the saved game, dropping anything unsaved",
+			callback:    commandLoad,
+		},
+		"profile": {
+			name:        "profile [list | new <name> | switch <name> | delete <name>]",
+			description: "Shows the current trainer profile, or manages the saved profiles",
+			callback:    commandProfile,
+		},
+		"battle": {
+			name:        "battle <pokemon> [opponent] [--level n]",
+			description: "Battles one of your pokemon against the wild pokemon, or any other pokemon",
+			callback:    commandBattle,
+		},
+		"type": {
+			name:        "type <name>",
+			description: "Displays what a type is strong and weak against",
+			callback:    commandType,
+		},
+		"matchup": {
+			name:        "matchup <attacker> <defender>",
+			description: "Displays how effective an attacker's types are against a defender (pokemon or type names)",
+			callback:    commandMatchup,
+		},
+		"party": {
+			name:        "party",
+			description: "Displays the pokemon in your party, the first one is your lead",
+			callback:    commandParty,
+		},
+		"box": {
+			name:        "box [number]",
+			description: "Displays the pokemon in your pc boxes",
+			callback:    commandBox,
+		},
+		"deposit": {
+			name:        "deposit <id|name> [--box number]",
+			description: "Moves a pokemon from your party to a box",
+			callback:    commandDeposit,
+		},
+		"withdraw": {
+			name:        "withdraw <id|name>",
+			description: "Moves a pokemon from a box to your party",
+			callback:    commandWithdraw,
+		},
+		"release": {
+			name:        "release <id|name>",
+			description: "Releases a caught pokemon back into the wild",
+			callback:    commandRelease,
+		},
+		"nickname": {
+			name:        "nickname <id> [name]",
+			description: "Gives a caught pokemon a nickname, or removes it",
+			callback:    commandNickname,
+			keepCase:    true,
+		},
+		"evolve": {
+			name:        "evolve <pokemon> [--into name] [--item name] [--trade]",
+			description: "Evolves a caught pokemon that meets the conditions of its evolution chain",
+			callback:    commandEvolve,
+		},
+		"version": {
+			name:        "version [<name> | all | list]",
+			description: "Shows or sets the game version that explore, encounter, learnset and sprites are limited to",
+			callback:    commandVersion,
+		},
+		"learnset": {
+			name:        "learnset <pokemon> [--method level-up|machine|egg|tutor]",
+			description: "Displays the moves a pokemon learns in the selected game version",
+			callback:    commandLearnset,
+		},
+		"set": {
+			name:        "set [<setting> <value>]",
+			description: "Shows the settings, or changes one (set output json, set sandbox on, set player ffplay -nodisp -autoexit -)",
+			callback:    commandSet,
+			keepCase:    true,
+		},
+	}
+}
+
+type areaListResult struct {
+	Areas []string `json:"areas"`
+}
+
+func (r areaListResult) renderText(w io.Writer) {
+	fmt.Fprintln(w)
+	for _, area := range r.Areas {
+		fmt.Fprintln(w, area)
+	}
+	fmt.Fprintln(w)
+}
+
+func (r areaListResult) pipeValues() []string {
+	return r.Areas
+}
+
+func (r areaListResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, area := range r.Areas {
+		rows = append(rows, []string{area})
+	}
+	return []string{"AREA"}, rows
+}
+
+type exploreResult struct {
+	Area    string   `json:"area"`
+	Pokemon []string `json:"pokemon"`
+}
+
+func (r exploreResult) renderText(w io.Writer) {
+	fmt.Fprintln(w)
+	for _, name := range r.Pokemon {
+		fmt.Fprintln(w, name)
+	}
+	fmt.Fprintln(w)
+}
+
+func (r exploreResult) pipeValues() []string {
+	return r.Pokemon
+}
+
+func (r exploreResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, name := range r.Pokemon {
+		rows = append(rows, []string{r.Area, name})
+	}
+	return []string{"AREA", "POKEMON"}, rows
+}
+
+type helpResult struct {
+	Commands []helpEntry `json:"commands"`
+}
+
+type helpEntry struct {
+	Usage       string   `json:"usage"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Description string   `json:"description"`
+}
+
+func (r helpResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, "Welcome to the Pokedex!")
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w)
+	for _, cmd := range r.Commands {
+		if len(cmd.Aliases) > 0 {
+			fmt.Fprintf(w, "%s (%s): %s\n", cmd.Usage, strings.Join(cmd.Aliases, ", "), cmd.Description)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", cmd.Usage, cmd.Description)
+	}
+}
+
+func (r helpResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, cmd := range r.Commands {
+		rows = append(rows, []string{cmd.Usage, strings.Join(cmd.Aliases, ", "), cmd.Description})
+	}
+	return []string{"COMMAND", "ALIASES", "DESCRIPTION"}, rows
+}
+
+func commandExit(s *session, params ...string) (commandResult, error) {
+	if strings.Join(params, "") != "" {
+		return nil, fmt.Errorf("exit command does not take any parameters")
+	}
+	s.status("Closing the Pokedex... Goodbye!\n")
+	return nil, errExit
+}
+
+func commandExplore(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, nil, []string{"details"})
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("explore command requires an area id or name")
+	}
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("explore command only takes one area")
+	}
+
+	area, err := s.client.getLocationArea(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s.stats.AreasExplored++
+	if s.location != area.Name {
+		s.wild = nil
+	}
+	s.location = area.Name
+	result := exploreResult{Area: area.Name, Pokemon: []string{}}
+	for _, name := range areaPokemon(area, s.version) {
+		result.Pokemon = append(result.Pokemon, name)
+		s.pokedex.see(name, s.now())
+	}
+
+	if flags["details"] != "" {
+		return newExploreDetails(area, s.version), nil
+	}
+	return result, nil
+}
+
+func commandMap(s *session, params ...string) (commandResult, error) {
+	if strings.Join(params, "") != "" {
+		return nil, fmt.Errorf("map command does not take any parameters")
+	}
+	pageUrl := ""
+	if s.indexUrls.nextUrl == nil && s.indexUrls.prevUrl != nil {
+		return messageResult{Message: "No more areas to display"}, nil
+	}
+
+	if s.indexUrls.nextUrl != nil {
+		pageUrl = *s.indexUrls.nextUrl
+	}
+
+	return s.showAreaPage(pageUrl)
+}
+
+func commandMapb(s *session, params ...string) (commandResult, error) {
+	if strings.Join(params, "") != "" {
+		return nil, fmt.Errorf("mapb command does not take any parameters")
+	}
+	pageUrl := ""
+	if s.indexUrls.prevUrl == nil && s.indexUrls.nextUrl != nil {
+		return messageResult{Message: "No more areas to display"}, nil
+	}
+
+	if s.indexUrls.prevUrl != nil {
+		pageUrl = *s.indexUrls.prevUrl
+	}
+
+	return s.showAreaPage(pageUrl)
+}
+
+// showAreaPage fetches a page of areas for map and mapb and remembers the
+// neighbouring pages for the next call
+func (s *session) showAreaPage(pageUrl string) (commandResult, error) {
+	pokedexAreas, err := s.client.listLocationAreas(pageUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	s.indexUrls.nextUrl = pokedexAreas.Next
+	s.indexUrls.prevUrl = pokedexAreas.Previous
+
+	result := areaListResult{Areas: []string{}}
+	for _, area := range pokedexAreas.Results {
+		result.Areas = append(result.Areas, area.Name)
+	}
+
+	return result, nil
+}
+
+func commandHelp(s *session, params ...string) (commandResult, error) {
+	if strings.Join(params, "") != "" {
+		return nil, fmt.Errorf("help command does not take any parameters")
+	}
+	result := helpResult{}
+	for _, key := range sortedKeys(s.commands) {
+		cmdData := s.commands[key]
+		result.Commands = append(result.Commands, helpEntry{Usage: cmdData.name, Aliases: cmdData.aliases, Description: cmdData.description})
+	}
+	return result, nil
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "fixtures", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return data
+}
+
+func checkGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+	}
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("output does not match %s\nexpected:\n%s\ngot:\n%s", path, expected, actual)
+	}
+}
+
+// newFixtureServer serves testdata/fixtures the way the pokeapi serves its
+// resources, /pokemon/pikachu is answered with pokemon-pikachu.json
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.ReplaceAll(strings.Trim(r.URL.Path, "/"), "/", "-") + ".json"
+		data, err := os.ReadFile(filepath.Join("testdata", "fixtures", name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestSession returns a session talking to the fixture server, with the
+// shake delay removed and a fixed random seed. it starts in sandbox mode so
+// tests can catch without travelling first
+func newTestSession(t *testing.T) (*session, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	server := newFixtureServer(t)
+	var out, errOut bytes.Buffer
+	s := newSession(newPokeClient(server.URL, time.Minute), &out, &errOut)
+	s.rng = rand.New(rand.NewSource(1))
+	s.shakeDelay = 0
+	s.sandbox = true
+	s.now = func() time.Time {
+		return time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	}
+	return s, &out, &errOut
+}
+
+func addTestPokemon(t *testing.T, s *session, fixture string) {
+	t.Helper()
+	var pokemon pokePokemon
+	if err := json.Unmarshal(loadFixture(t, fixture), &pokemon); err != nil {
+		t.Fatalf("decoding fixture: %v", err)
+	}
+	if _, _, err := s.store(caughtPokemon{Name: pokemon.Name, CaughtAt: s.now()}); err != nil {
+		t.Fatalf("storing %s: %v", pokemon.Name, err)
+	}
+	s.pokedex.recordAttempt(pokemon.Name, true, s.now())
+}
+
+func TestCommandGolden(t *testing.T) {
+	cases := []struct {
+		name   string
+		format outputFormat
+		caught []string
+		lines  []string
+	}{
+		{name: "map", format: formatText, lines: []string{"map"}},
+		{name: "map_mapb", format: formatText, lines: []string{"map", "mapb"}},
+		{name: "explore", format: formatText, lines: []string{"explore canalave-city-area"}},
+		{name: "explore_json", format: formatJSON, lines: []string{"explore canalave-city-area"}},
+		{name: "explore_details", format: formatText, lines: []string{"explore canalave-city-area --details"}},
+		{name: "explore_details_table", format: formatTable, lines: []string{"explore canalave-city-area --details"}},
+		{name: "inspect", format: formatText, caught: []string{"pokemon-pikachu.json"}, lines: []string{"inspect pikachu"}},
+		{name: "inspect_individual", format: formatText, lines: []string{"catch pikachu --ball master", "nickname 1 sparky", "party", "inspect sparky"}},
+		{name: "inspect_yaml", format: formatYAML, caught: []string{"pokemon-pikachu.json"}, lines: []string{"inspect pikachu"}},
+		{name: "inspect_missing", format: formatText, lines: []string{"inspect pikachu"}},
+		{name: "inspect_seen", format: formatText, lines: []string{"explore canalave-city-area", "inspect magikarp"}},
+		{name: "learnset", format: formatText, lines: []string{"learnset pikachu"}},
+		{name: "learnset_table", format: formatTable, lines: []string{"version red", "learnset pikachu"}},
+		{name: "battle", format: formatText, caught: []string{"pokemon-pikachu.json"}, lines: []string{"battle pikachu magikarp --level 12"}},
+		{name: "type", format: formatText, lines: []string{"type electric"}},
+		{name: "matchup", format: formatText, lines: []string{"matchup pikachu gyarados", "matchup gyarados ground"}},
+		{name: "pokedex", format: formatText, caught: []string{"pokemon-pikachu.json"}, lines: []string{"explore canalave-city-area", "pokedex"}},
+		{name: "pokedex_caught", format: formatText, caught: []string{"pokemon-pikachu.json"}, lines: []string{"explore canalave-city-area", "pokedex caught"}},
+		{name: "pokedex_table", format: formatTable, caught: []string{"pokemon-pikachu.json"}, lines: []string{"explore canalave-city-area", "pokedex"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, out, errOut := newTestSession(t)
+			for _, fixture := range c.caught {
+				addTestPokemon(t, s, fixture)
+			}
+			s.output = c.format
+			for _, line := range c.lines {
+				if err := s.runLine(line); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			checkGolden(t, c.name, append(out.Bytes(), errOut.Bytes()...))
+		})
+	}
+}
+
+func TestRunStopsOnExit(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	err := s.run(strings.NewReader("bogus\nexit\nhelp\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Goodbye!") {
+		t.Errorf("expected the goodbye message, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "Welcome to the Pokedex!") {
+		t.Errorf("expected commands after exit to be ignored")
+	}
+	if errOut.String() != "Unknown command\n" {
+		t.Errorf("expected unknown command on the error writer, got %q", errOut.String())
+	}
+}
+
+func TestSessionsAreIndependent(t *testing.T) {
+	first, _, _ := newTestSession(t)
+	second, secondOut, _ := newTestSession(t)
+
+	done := make(chan error)
+	go func() {
+		done <- first.runLine("catch pikachu")
+	}()
+	if err := second.runLine("map"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := first.pokedex.entry("pikachu"); !ok {
+		t.Errorf("expected pikachu in the first session's pokedex")
+	}
+	if len(second.pokedex.entries) != 0 {
+		t.Errorf("expected the second session to have seen nothing")
+	}
+	if first.indexUrls.nextUrl != nil {
+		t.Errorf("expected map in the second session to leave the first session's pages alone")
+	}
+	if !strings.Contains(secondOut.String(), "canalave-city-area") {
+		t.Errorf("expected the second session to list areas, got %q", secondOut.String())
+	}
+}
+
+func TestChainingAndPipes(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	if err := s.runLine("map; mapb ; explore canalave-city-area | catch-all"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("unexpected errors: %s", errOut.String())
+	}
+	for _, expected := range []string{"eterna-city-area", "No more areas to display", "Throwing a Pokeball at tentacool...", "shellos"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in the output, got %q", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "\nmagikarp\n") {
+		t.Errorf("expected the piped explore output to not be rendered")
+	}
+}
+
+func TestPipeErrors(t *testing.T) {
+	cases := []struct {
+		line     string
+		expected string
+	}{
+		{line: "help | catch-all", expected: "the output of help cannot be piped"},
+		{line: "explore canalave-city-area |", expected: "missing command in pipe"},
+		{line: "explore nowhere | catch-all", expected: "404 Not Found"},
+	}
+	for _, c := range cases {
+		s, _, errOut := newTestSession(t)
+		if err := s.runLine(c.line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(errOut.String(), c.expected) {
+			t.Errorf("%q: expected %q, got %q", c.line, c.expected, errOut.String())
+		}
+	}
+}
+
+func TestCatchOnlyRecordsCaughtPokemon(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	caught := 0
+	for i := 0; i < 5; i++ {
+		result, err := s.throwBall("magikarp", defaultCatchOptions())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Caught {
+			caught++
+		}
+		if len(s.caught) != caught {
+			t.Errorf("expected %d magikarp in the collection, got %d", caught, len(s.caught))
+		}
+		inCollection := caught > 0
+		entry, _ := s.pokedex.entry("magikarp")
+		if entry.Attempts != i+1 {
+			t.Errorf("expected %d attempts, got %d", i+1, entry.Attempts)
+		}
+		if inCollection != entry.caught() {
+			t.Errorf("pokedex and caught pokemon disagree")
+		}
+		if result.Caught && !inCollection {
+			t.Errorf("expected a caught magikarp to be recorded")
+		}
+		if !result.Caught && !inCollection && entry.status() != "seen" {
+			t.Errorf("expected an escaped magikarp to only be seen")
+		}
+	}
+}
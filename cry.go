@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type cryResult struct {
+	Pokemon string `json:"pokemon"`
+	Url     string `json:"url"`
+	Legacy  bool   `json:"legacy,omitempty"`
+	// Path is where the cry was saved, empty when it was only played
+	Path   string `json:"path,omitempty"`
+	Played bool   `json:"played,omitempty"`
+	Bytes  int    `json:"bytes"`
+}
+
+func (r cryResult) renderText(w io.Writer) {
+	if r.Path != "" {
+		fmt.Fprintf(w, "Saved %s's cry to %s (%s)\n", r.Pokemon, r.Path, plural(r.Bytes, "byte"))
+	}
+	if r.Played {
+		fmt.Fprintf(w, "%s cried!\n", r.Pokemon)
+	}
+}
+
+func (r cryResult) pipeValues() []string {
+	if r.Path == "" {
+		return []string{}
+	}
+	return []string{r.Path}
+}
+
+func (r cryResult) tableRows() ([]string, [][]string) {
+	return fieldRows(
+		"pokemon", r.Pokemon,
+		"url", r.Url,
+		"path", r.Path,
+		"played", fmt.Sprint(r.Played),
+		"bytes", fmt.Sprint(r.Bytes),
+	)
+}
+
+// play pipes audio into the player command from the config file, which
+// reads it from its standard input, e.g. "ffplay -nodisp -autoexit -"
+func (s *session) play(audio []byte) error {
+	args := strings.Fields(s.player)
+	if len(args) == 0 {
+		return fmt.Errorf("there is no player set, set one with set player <command> (e.g. set player ffplay -nodisp -autoexit -)")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(audio)
+	cmd.Stdout = s.out
+	cmd.Stderr = s.errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("playing with %s: %w", args[0], err)
+	}
+	return nil
+}
+
+func commandCry(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"out"}, []string{"legacy", "play"})
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("cry command requires a pokemon name, or the id or nickname of a caught pokemon")
+	}
+	legacy, play := flags["legacy"] != "", flags["play"] != ""
+
+	name := strings.ToLower(args[0])
+	if caught, err := s.findCaught(name); err == nil {
+		name = caught.Name
+	}
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return nil, err
+	}
+	url := pokemon.Cries.Latest
+	if legacy {
+		url = pokemon.Cries.Legacy
+	}
+	if url == "" {
+		if legacy {
+			return nil, fmt.Errorf("%s has no legacy cry", pokemon.Name)
+		}
+		return nil, fmt.Errorf("%s has no cry", pokemon.Name)
+	}
+	audio, err := s.client.getRaw(url)
+	if err != nil {
+		return nil, err
+	}
+
+	result := cryResult{Pokemon: pokemon.Name, Url: url, Legacy: legacy, Bytes: len(audio)}
+	// the cry is saved unless it is only being played
+	path, save := flags["out"]
+	if !save && !play {
+		path, save = pokemon.Name+".ogg", true
+		if legacy {
+			path = pokemon.Name + "-legacy.ogg"
+		}
+	}
+	if save {
+		if err := os.WriteFile(path, audio, 0644); err != nil {
+			return nil, err
+		}
+		result.Path = path
+	}
+	if play {
+		if err := s.play(audio); err != nil {
+			return nil, err
+		}
+		result.Played = true
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testCryUrl       = "https://raw.githubusercontent.com/PokeAPI/cries/main/cries/pokemon/latest/25.ogg"
+	testLegacyCryUrl = "https://raw.githubusercontent.com/PokeAPI/cries/main/cries/pokemon/legacy/25.ogg"
+)
+
+func TestCrySavesFile(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	// the cries live on github, the cache stands in for the download
+	s.client.cache.Add(testCryUrl, []byte("OggS latest"))
+	s.client.cache.Add(testLegacyCryUrl, []byte("OggS legacy"))
+	dir := t.TempDir()
+
+	// the paths keep their case, the temporary directory's name has some
+	lines := []string{
+		"cry Pikachu --out " + filepath.Join(dir, "Latest.ogg"),
+		"cry pikachu --LEGACY --out=" + filepath.Join(dir, "legacy.ogg"),
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for file, expected := range map[string]string{"Latest.ogg": "OggS latest", "legacy.ogg": "OggS legacy"} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatalf("reading %s: %v", file, err)
+		}
+		if string(data) != expected {
+			t.Errorf("expected %s to hold %q, got %q", file, expected, data)
+		}
+	}
+	if !strings.Contains(out.String(), "Saved pikachu's cry to "+filepath.Join(dir, "Latest.ogg")+" (11 bytes)") {
+		t.Errorf("expected the saved file to be reported, got %q", out.String())
+	}
+}
+
+func TestCryPlays(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.client.cache.Add(testCryUrl, []byte("OggS latest"))
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+
+	if err := s.runLine("cry 1 --play"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "there is no player set") {
+		t.Errorf("expected a missing player error, got %q", errOut.String())
+	}
+
+	// wc stands in for an audio player reading the cry from its input
+	for _, line := range []string{"set player wc -c", "cry 1 --play"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !strings.Contains(out.String(), "11\npikachu cried!") {
+		t.Errorf("expected the cry to be piped into the player, got %q", out.String())
+	}
+}
+
+func TestPlayerIsSavedInConfig(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.configPath = filepath.Join(t.TempDir(), "config.json")
+	if err := s.runLine("set player /Applications/VLC.app/Contents/MacOS/VLC --play-and-exit -"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reloaded, _, _ := newTestSession(t)
+	reloaded.configPath = s.configPath
+	if err := reloaded.loadConfig(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reloaded.player != "/Applications/VLC.app/Contents/MacOS/VLC --play-and-exit -" {
+		t.Errorf("expected the player as typed, got %q", reloaded.player)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// defaultEncounterMethod is used when an area has grass to walk through,
+// otherwise the area's first encounter method is
+const defaultEncounterMethod = "walk"
+
+// encounterSlot is one way a pokemon can be met in an area
+type encounterSlot struct {
+	Pokemon    string   `json:"pokemon"`
+	Version    string   `json:"version"`
+	Method     string   `json:"method"`
+	Chance     int      `json:"chance"`
+	MinLevel   int      `json:"min_level"`
+	MaxLevel   int      `json:"max_level"`
+	Conditions []string `json:"conditions"`
+}
+
+// wildPokemon is the pokemon the trainer is currently facing
+type wildPokemon struct {
+	Name    string `json:"name"`
+	Level   int    `json:"level"`
+	Method  string `json:"method"`
+	Version string `json:"version"`
+	Area    string `json:"area"`
+	Shiny   bool   `json:"shiny,omitempty"`
+}
+
+// encounterSlots flattens the encounter details of an area
+func encounterSlots(area pokeLocationArea) []encounterSlot {
+	slots := []encounterSlot{}
+	for _, encounter := range area.PokemonEncounters {
+		for _, versionDetail := range encounter.VersionDetails {
+			for _, detail := range versionDetail.EncounterDetails {
+				conditions := []string{}
+				for _, condition := range detail.ConditionValues {
+					conditions = append(conditions, condition.Name)
+				}
+				slots = append(slots, encounterSlot{
+					Pokemon:    encounter.Pokemon.Name,
+					Version:    versionDetail.Version.Name,
+					Method:     detail.Method.Name,
+					Chance:     detail.Chance,
+					MinLevel:   detail.MinLevel,
+					MaxLevel:   detail.MaxLevel,
+					Conditions: conditions,
+				})
+			}
+		}
+	}
+	return slots
+}
+
+// resourceID reads the id at the end of a pokeapi url such as
+// https://pokeapi.co/api/v2/version/14/
+func resourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, _ := strconv.Atoi(parts[len(parts)-1])
+	return id
+}
+
+// latestVersion picks the newest game version (highest id) an area has
+// encounter data for with the given method
+func latestVersion(area pokeLocationArea, method string) string {
+	latest, latestID := "", -1
+	for _, encounter := range area.PokemonEncounters {
+		for _, versionDetail := range encounter.VersionDetails {
+			for _, detail := range versionDetail.EncounterDetails {
+				id := resourceID(versionDetail.Version.Url)
+				if detail.Method.Name == method && id > latestID {
+					latest, latestID = versionDetail.Version.Name, id
+				}
+			}
+		}
+	}
+	return latest
+}
+
+// defaultMethod picks walking when possible, or the area's first method
+func defaultMethod(slots []encounterSlot) string {
+	for _, slot := range slots {
+		if slot.Method == defaultEncounterMethod {
+			return defaultEncounterMethod
+		}
+	}
+	if len(slots) == 0 {
+		return defaultEncounterMethod
+	}
+	return slots[0].Method
+}
+
+// filterVersion keeps the slots for one version, or all of them when no
+// version is given
+func filterVersion(slots []encounterSlot, version string) []encounterSlot {
+	if version == "" {
+		return slots
+	}
+	filtered := []encounterSlot{}
+	for _, slot := range slots {
+		if slot.Version == version {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// filterSlots keeps the slots for one version and method
+func filterSlots(slots []encounterSlot, version, method string) []encounterSlot {
+	filtered := []encounterSlot{}
+	for _, slot := range slots {
+		if slot.Version == version && slot.Method == method {
+			filtered = append(filtered, slot)
+		}
+	}
+	return filtered
+}
+
+// rollEncounter picks a slot weighted by its chance and a level within its
+// range. slots the api gives no chance at all are equally likely
+func (s *session) rollEncounter(slots []encounterSlot) (encounterSlot, int) {
+	total := 0
+	for _, slot := range slots {
+		total += slot.Chance
+	}
+	picked := slots[len(slots)-1]
+	if total == 0 {
+		picked = slots[s.rng.Intn(len(slots))]
+	} else {
+		roll := s.rng.Intn(total)
+		for _, slot := range slots {
+			if roll < slot.Chance {
+				picked = slot
+				break
+			}
+			roll -= slot.Chance
+		}
+	}
+	level := picked.MinLevel
+	if picked.MaxLevel > picked.MinLevel {
+		level += s.rng.Intn(picked.MaxLevel - picked.MinLevel + 1)
+	}
+	return picked, level
+}
+
+type encounterResult struct {
+	Wild wildPokemon `json:"wild"`
+}
+
+func (r encounterResult) renderText(w io.Writer) {
+	if r.Wild.Shiny {
+		fmt.Fprintf(w, "A wild shiny %s (level %d) appeared!\n", r.Wild.Name, r.Wild.Level)
+		return
+	}
+	fmt.Fprintf(w, "A wild %s (level %d) appeared!\n", r.Wild.Name, r.Wild.Level)
+}
+
+func (r encounterResult) pipeValues() []string {
+	return []string{r.Wild.Name}
+}
+
+func (r encounterResult) tableRows() ([]string, [][]string) {
+	return fieldRows(
+		"pokemon", r.Wild.Name,
+		"level", strconv.Itoa(r.Wild.Level),
+		"method", r.Wild.Method,
+		"version", r.Wild.Version,
+		"area", r.Wild.Area,
+		"shiny", strconv.FormatBool(r.Wild.Shiny),
+	)
+}
+
+func commandEncounter(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"method", "version"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("encounter command only takes --method and --version")
+	}
+	if s.location == "" {
+		return nil, fmt.Errorf("you are not in any area yet, explore or travel to one first")
+	}
+	area, err := s.client.getLocationArea(s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	allSlots := encounterSlots(area)
+	version, ok := flags["version"]
+	if !ok {
+		version = s.version
+	}
+	method, ok := flags["method"]
+	if !ok {
+		method = defaultMethod(filterVersion(allSlots, version))
+	}
+	if version == "" {
+		version = latestVersion(area, method)
+	}
+	slots := filterSlots(allSlots, version, method)
+	if len(slots) == 0 {
+		if version == "" {
+			return nil, fmt.Errorf("no pokemon can be met by %s in %s", method, area.Name)
+		}
+		return nil, fmt.Errorf("no pokemon can be met by %s in %s in %s", method, area.Name, version)
+	}
+
+	slot, level := s.rollEncounter(slots)
+	shiny, err := s.rollShiny(slot.Version)
+	if err != nil {
+		return nil, err
+	}
+	s.wild = &wildPokemon{
+		Name:    slot.Pokemon,
+		Level:   level,
+		Method:  slot.Method,
+		Version: slot.Version,
+		Area:    area.Name,
+		Shiny:   shiny,
+	}
+	s.pokedex.see(slot.Pokemon, s.now())
+	return encounterResult{Wild: *s.wild}, nil
+}
+
+// methodRate is how likely an encounter method is to turn up a pokemon at
+// all, e.g. per step in grass or per cast of a rod
+type methodRate struct {
+	Method  string `json:"method"`
+	Version string `json:"version"`
+	Rate    int    `json:"rate"`
+}
+
+type exploreDetailsResult struct {
+	Area        string          `json:"area"`
+	MethodRates []methodRate    `json:"method_rates"`
+	Encounters  []encounterSlot `json:"encounters"`
+}
+
+// newExploreDetails describes an area's encounters, only those of one game
+// version when version is set
+func newExploreDetails(area pokeLocationArea, version string) exploreDetailsResult {
+	result := exploreDetailsResult{
+		Area:        area.Name,
+		MethodRates: []methodRate{},
+		Encounters:  filterVersion(encounterSlots(area), version),
+	}
+	for _, methodRates := range area.EncounterMethodRates {
+		for _, versionDetail := range methodRates.VersionDetails {
+			if version != "" && versionDetail.Version.Name != version {
+				continue
+			}
+			result.MethodRates = append(result.MethodRates, methodRate{
+				Method:  methodRates.EncounterMethod.Name,
+				Version: versionDetail.Version.Name,
+				Rate:    versionDetail.Rate,
+			})
+		}
+	}
+	return result
+}
+
+func (r exploreDetailsResult) renderText(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, r.Area)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Encounter rates:")
+	rows := [][]string{}
+	for _, rate := range r.MethodRates {
+		rows = append(rows, []string{"  " + rate.Method, rate.Version, fmt.Sprintf("%d%%", rate.Rate)})
+	}
+	writeTable(w, []string{"  METHOD", "VERSION", "RATE"}, rows)
+
+	pokemon := r.pipeValues()
+	for _, name := range pokemon {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, name)
+		rows := [][]string{}
+		for _, slot := range r.Encounters {
+			if slot.Pokemon == name {
+				rows = append(rows, []string{"  " + slot.Version, slot.Method, slot.levels(), fmt.Sprintf("%d%%", slot.Chance), slot.conditions()})
+			}
+		}
+		writeTable(w, []string{"  VERSION", "METHOD", "LEVELS", "CHANCE", "CONDITIONS"}, rows)
+	}
+	fmt.Fprintln(w)
+}
+
+// pipeValues lists each pokemon once, in the order the area lists them
+func (r exploreDetailsResult) pipeValues() []string {
+	names := []string{}
+	listed := map[string]bool{}
+	for _, slot := range r.Encounters {
+		if !listed[slot.Pokemon] {
+			listed[slot.Pokemon] = true
+			names = append(names, slot.Pokemon)
+		}
+	}
+	return names
+}
+
+func (r exploreDetailsResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, slot := range r.Encounters {
+		rows = append(rows, []string{slot.Pokemon, slot.Version, slot.Method, slot.levels(), strconv.Itoa(slot.Chance), slot.conditions()})
+	}
+	return []string{"POKEMON", "VERSION", "METHOD", "LEVELS", "CHANCE", "CONDITIONS"}, rows
+}
+
+func (slot encounterSlot) levels() string {
+	if slot.MinLevel == slot.MaxLevel {
+		return strconv.Itoa(slot.MinLevel)
+	}
+	return fmt.Sprintf("%d-%d", slot.MinLevel, slot.MaxLevel)
+}
+
+func (slot encounterSlot) conditions() string {
+	if len(slot.Conditions) == 0 {
+		return "-"
+	}
+	return strings.Join(slot.Conditions, ", ")
+}
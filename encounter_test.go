@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRollEncounterFollowsChances(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	area, err := s.client.getLocationArea("canalave-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slots := filterSlots(encounterSlots(area), "diamond", "surf")
+	if len(slots) != 3 {
+		t.Fatalf("expected 3 surf slots in diamond, got %d", len(slots))
+	}
+
+	const rolls = 10000
+	counts := map[string]int{}
+	for i := 0; i < rolls; i++ {
+		slot, level := s.rollEncounter(slots)
+		if level < slot.MinLevel || level > slot.MaxLevel {
+			t.Fatalf("level %d outside of %d-%d", level, slot.MinLevel, slot.MaxLevel)
+		}
+		counts[slot.Pokemon]++
+	}
+	expected := map[string]float64{"tentacool": 0.6, "wingull": 0.3, "shellos": 0.1}
+	for name, share := range expected {
+		actual := float64(counts[name]) / rolls
+		if math.Abs(actual-share) > 0.03 {
+			t.Errorf("expected %s about %.0f%% of the time, got %.1f%%", name, share*100, actual*100)
+		}
+	}
+}
+
+func TestRollEncounterWithoutChances(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	slots := []encounterSlot{
+		{Pokemon: "tentacool", MinLevel: 20, MaxLevel: 20},
+		{Pokemon: "wingull", MinLevel: 20, MaxLevel: 20},
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		slot, _ := s.rollEncounter(slots)
+		seen[slot.Pokemon] = true
+	}
+	if !seen["tentacool"] || !seen["wingull"] {
+		t.Errorf("expected both slots to come up when neither has a chance, got %v", seen)
+	}
+}
+
+func TestEncounterCommand(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.sandbox = false
+	lines := []string{
+		"encounter",
+		"travel canalave-city-area",
+		"walk --method surf --version platinum",
+		"catch --ball master",
+		"encounter --method walk",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, expected := range []string{"A wild tentacool (level ", "Throwing a Master Ball at tentacool...", "tentacool was caught!"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	}
+	for _, expected := range []string{"you are not in any area yet", "no pokemon can be met by walk in canalave-city-area"} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, errOut.String())
+		}
+	}
+	if s.wild != nil {
+		t.Errorf("expected the caught wild pokemon to be gone")
+	}
+}
+
+func TestDefaultEncounterVersionAndMethod(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	area, err := s.client.getLocationArea("canalave-city-area")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method := defaultMethod(encounterSlots(area)); method != "surf" {
+		t.Errorf("expected surf for an area without grass, got %s", method)
+	}
+	if version := latestVersion(area, "surf"); version != "platinum" {
+		t.Errorf("expected platinum as the newest version, got %s", version)
+	}
+}
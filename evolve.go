@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type pokeEvolutionChain struct {
+	ID    int           `json:"id"`
+	Chain pokeChainLink `json:"chain"`
+}
+
+// pokeChainLink is one species in an evolution chain, with the species it
+// can evolve into and what it takes to get there
+type pokeChainLink struct {
+	Species          pokeNamedResource     `json:"species"`
+	EvolutionDetails []pokeEvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []pokeChainLink       `json:"evolves_to"`
+}
+
+type pokeEvolutionDetail struct {
+	Trigger            pokeNamedResource  `json:"trigger"`
+	MinLevel           int                `json:"min_level"`
+	MinHappiness       int                `json:"min_happiness"`
+	Item               *pokeNamedResource `json:"item"`
+	HeldItem           *pokeNamedResource `json:"held_item"`
+	TimeOfDay          string             `json:"time_of_day"`
+	KnownMove          *pokeNamedResource `json:"known_move"`
+	KnownMoveType      *pokeNamedResource `json:"known_move_type"`
+	Location           *pokeNamedResource `json:"location"`
+	Gender             *int               `json:"gender"`
+	NeedsOverworldRain bool               `json:"needs_overworld_rain"`
+	TradeSpecies       *pokeNamedResource `json:"trade_species"`
+}
+
+// getEvolutionChain fetches a chain by the url a species links to, going
+// through the client's own base url so the lookup is cached like the rest
+func (c *pokeClient) getEvolutionChain(url string) (pokeEvolutionChain, error) {
+	var chain pokeEvolutionChain
+	err := c.get(c.resourceUrl("evolution-chain", fmt.Sprint(resourceID(url))), &chain)
+	return chain, err
+}
+
+// find returns the link of a species somewhere in the chain
+func (l pokeChainLink) find(species string) (pokeChainLink, bool) {
+	if l.Species.Name == species {
+		return l, true
+	}
+	for _, next := range l.EvolvesTo {
+		if link, found := next.find(species); found {
+			return link, true
+		}
+	}
+	return pokeChainLink{}, false
+}
+
+// timeOfDay follows the games' clock, day from 4 in the morning to 8 at night
+func timeOfDay(at time.Time) string {
+	if hour := at.Hour(); hour >= 4 && hour < 20 {
+		return "day"
+	}
+	return "night"
+}
+
+// evolutionGender names the gender an evolution detail asks for, which the
+// api numbers 1 for female and 2 for male
+func evolutionGender(gender int) string {
+	if gender == 1 {
+		return "female"
+	}
+	return "male"
+}
+
+type evolveOptions struct {
+	into  string
+	item  string
+	trade bool
+}
+
+// unmetConditions lists what a caught pokemon still lacks to evolve in the
+// way an evolution detail describes, nothing when it can evolve now
+func (s *session) unmetConditions(pokemon caughtPokemon, detail pokeEvolutionDetail, opts evolveOptions) []string {
+	unmet := []string{}
+	switch detail.Trigger.Name {
+	case "level-up":
+	case "use-item":
+		if detail.Item != nil && opts.item != detail.Item.Name {
+			unmet = append(unmet, fmt.Sprintf("use a %s (--item %s)", detail.Item.Name, detail.Item.Name))
+		} else if detail.Item != nil && !s.hasItem(detail.Item.Name) {
+			unmet = append(unmet, fmt.Sprintf("have a %s (%s)", detail.Item.Name, prizeHint))
+		}
+	case "trade":
+		if !opts.trade {
+			unmet = append(unmet, "be traded (--trade)")
+		}
+	default:
+		unmet = append(unmet, fmt.Sprintf("%s, which this pokedex can not do", detail.Trigger.Name))
+	}
+
+	if detail.MinLevel > pokemon.Level {
+		unmet = append(unmet, fmt.Sprintf("reach level %d", detail.MinLevel))
+	}
+	if detail.MinHappiness > pokemon.Friendship {
+		unmet = append(unmet, fmt.Sprintf("reach %d friendship (it has %d)", detail.MinHappiness, pokemon.Friendship))
+	}
+	if detail.TimeOfDay != "" && detail.TimeOfDay != timeOfDay(s.now()) {
+		unmet = append(unmet, fmt.Sprintf("evolve during the %s", detail.TimeOfDay))
+	}
+	if detail.HeldItem != nil && !s.hasItem(detail.HeldItem.Name) {
+		unmet = append(unmet, fmt.Sprintf("hold a %s (%s)", detail.HeldItem.Name, prizeHint))
+	}
+	// pokemon caught before genders were kept have none, and can not
+	// take a gendered evolution
+	if detail.Gender != nil && pokemon.Gender != evolutionGender(*detail.Gender) {
+		unmet = append(unmet, fmt.Sprintf("be %s", evolutionGender(*detail.Gender)))
+	}
+	// the rest depend on things the pokedex does not keep track of
+	if detail.KnownMove != nil {
+		unmet = append(unmet, fmt.Sprintf("know %s", detail.KnownMove.Name))
+	}
+	if detail.KnownMoveType != nil {
+		unmet = append(unmet, fmt.Sprintf("know a %s move", detail.KnownMoveType.Name))
+	}
+	if detail.Location != nil {
+		unmet = append(unmet, fmt.Sprintf("level up at %s", detail.Location.Name))
+	}
+	if detail.NeedsOverworldRain {
+		unmet = append(unmet, "level up in the rain")
+	}
+	if detail.TradeSpecies != nil {
+		unmet = append(unmet, fmt.Sprintf("be traded for a %s", detail.TradeSpecies.Name))
+	}
+	return unmet
+}
+
+// battlesPerPrize is how many battles a trainer wins for every evolution
+// item they are given
+const battlesPerPrize = 5
+
+// prizeHint tells the trainer where evolution items come from
+var prizeHint = fmt.Sprintf("won as a prize every %d battles", battlesPerPrize)
+
+// evolutionItems are the items pokemon evolve with, or hold while they
+// level up or are traded
+var evolutionItems = []string{
+	"fire-stone", "water-stone", "thunder-stone", "leaf-stone", "moon-stone",
+	"sun-stone", "shiny-stone", "dusk-stone", "dawn-stone", "ice-stone",
+	"oval-stone", "kings-rock", "metal-coat", "dragon-scale", "up-grade",
+	"dubious-disc", "protector", "electirizer", "magmarizer", "reaper-cloth",
+	"razor-claw", "razor-fang", "deep-sea-tooth", "deep-sea-scale", "prism-scale",
+}
+
+// prizeItem picks the evolution item a battle prize is: one the winning
+// pokemon can evolve with when it has any, otherwise any of them
+func (s *session) prizeItem(name string) (string, error) {
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return "", err
+	}
+	species, err := s.client.getSpecies(pokemon.Species.Name)
+	if err != nil {
+		return "", err
+	}
+	chain, err := s.client.getEvolutionChain(species.EvolutionChain.Url)
+	if err != nil {
+		return "", err
+	}
+	items := []string{}
+	if link, found := chain.Chain.find(species.Name); found {
+		for _, next := range link.EvolvesTo {
+			for _, detail := range next.EvolutionDetails {
+				if detail.Item != nil {
+					items = append(items, detail.Item.Name)
+				}
+				if detail.HeldItem != nil {
+					items = append(items, detail.HeldItem.Name)
+				}
+			}
+		}
+	}
+	if len(items) == 0 {
+		items = evolutionItems
+	}
+	return items[s.rng.Intn(len(items))], nil
+}
+
+// hasItem reports whether the trainer has an item, which is always the case
+// in sandbox mode
+func (s *session) hasItem(item string) bool {
+	return s.sandbox || s.inventory[item] > 0
+}
+
+// useItem takes an item out of the inventory when there is one, in sandbox
+// mode pokemon can evolve without
+func (s *session) useItem(item string) {
+	if s.inventory[item] > 0 {
+		s.inventory[item]--
+	}
+}
+
+type evolveResult struct {
+	ID    int    `json:"id"`
+	From  string `json:"from"`
+	Into  string `json:"into"`
+	Level int    `json:"level"`
+}
+
+func (r evolveResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "What? %s is evolving!\n", r.From)
+	fmt.Fprintf(w, "Congratulations! Your %s evolved into %s!\n", r.From, r.Into)
+}
+
+func (r evolveResult) pipeValues() []string {
+	return []string{strconv.Itoa(r.ID)}
+}
+
+func (r evolveResult) tableRows() ([]string, [][]string) {
+	return fieldRows("from", r.From, "into", r.Into, "level", fmt.Sprint(r.Level))
+}
+
+func commandEvolve(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"into", "item"}, []string{"trade"})
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("evolve command requires the id or name of a caught pokemon")
+	}
+	opts := evolveOptions{into: flags["into"], item: flags["item"], trade: flags["trade"] != ""}
+
+	caught, err := s.findCaught(args[0])
+	if err != nil {
+		return nil, err
+	}
+	pokemon, err := s.client.getPokemon(caught.Name)
+	if err != nil {
+		return nil, err
+	}
+	species, err := s.client.getSpecies(pokemon.Species.Name)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.client.getGrowthRate(species.GrowthRate.Name)
+	if err != nil {
+		return nil, err
+	}
+	caught.normalize(species, rate)
+	chain, err := s.client.getEvolutionChain(species.EvolutionChain.Url)
+	if err != nil {
+		return nil, err
+	}
+	link, found := chain.Chain.find(species.Name)
+	if !found || len(link.EvolvesTo) == 0 {
+		return nil, fmt.Errorf("%s does not evolve", caught.Name)
+	}
+
+	reasons := []string{}
+	for _, next := range link.EvolvesTo {
+		if opts.into != "" && next.Species.Name != opts.into {
+			continue
+		}
+		for _, detail := range next.EvolutionDetails {
+			unmet := s.unmetConditions(caught, detail, opts)
+			if len(unmet) > 0 {
+				reasons = append(reasons, fmt.Sprintf("into %s it needs to %s", next.Species.Name, strings.Join(unmet, ", ")))
+				continue
+			}
+			return s.evolve(caught, next.Species.Name, detail)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("%s does not evolve into %s", caught.Name, opts.into)
+	}
+	return nil, fmt.Errorf("%s can not evolve yet: %s", caught.Name, strings.Join(reasons, "; "))
+}
+
+// evolve replaces a caught pokemon with its evolved form. the new form keeps
+// the id, level, experience, friendship and catch date of the old one
+func (s *session) evolve(caught caughtPokemon, into string, detail pokeEvolutionDetail) (commandResult, error) {
+	species, err := s.client.getSpecies(into)
+	if err != nil {
+		return nil, err
+	}
+	name := species.defaultPokemon()
+	if detail.Trigger.Name == "use-item" && detail.Item != nil {
+		s.useItem(detail.Item.Name)
+	}
+	if detail.HeldItem != nil {
+		s.useItem(detail.HeldItem.Name)
+	}
+
+	from := caught.Name
+	caught.Name = name
+	caught.EvolvedFrom = append(caught.EvolvedFrom, from)
+	s.caught[caught.ID] = caught
+	s.pokedex.register(name, s.now())
+	if caught.Shiny {
+		s.pokedex.registerShiny(name)
+	}
+	return evolveResult{ID: caught.ID, From: from, Into: name, Level: caught.Level}, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvolveByLevel(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.inventory["master-ball"] = 2
+	lines := []string{
+		"catch magikarp --ball master --level 19",
+		"evolve magikarp",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !strings.Contains(errOut.String(), "magikarp can not evolve yet: into gyarados it needs to reach level 20") {
+		t.Errorf("expected the missing level to be reported, got %q", errOut.String())
+	}
+
+	if _, err := s.gainExperience(1, 1800); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.runLine("evolve magikarp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Congratulations! Your magikarp evolved into gyarados!") {
+		t.Errorf("expected magikarp to evolve, got %q", out.String())
+	}
+	gyarados := s.caught[1]
+	if gyarados.Name != "gyarados" || len(s.caught) != 1 {
+		t.Fatalf("expected magikarp to be replaced by gyarados, got %v", s.caught)
+	}
+	if gyarados.Level != 20 || !gyarados.CaughtAt.Equal(s.now()) || len(gyarados.EvolvedFrom) != 1 || gyarados.EvolvedFrom[0] != "magikarp" {
+		t.Errorf("expected gyarados to keep magikarp's history, got %+v", gyarados)
+	}
+	if lead, _ := s.lead(); lead.Name != "gyarados" {
+		t.Errorf("expected the lead to follow the evolution, got %q", lead.Name)
+	}
+	if entry, _ := s.pokedex.entry("gyarados"); entry == nil || !entry.caught() {
+		t.Errorf("expected gyarados to be registered in the pokedex")
+	}
+	if err := s.runLine("inspect 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Evolved from: magikarp\n") {
+		t.Errorf("expected inspect to show the evolution history, got %q", out.String())
+	}
+}
+
+func TestEvolveConditions(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.sandbox = false
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	pikachu := s.caught[1]
+	pikachu.Level = 30
+	s.caught[1] = pikachu
+
+	_, err := commandEvolve(s, "pikachu")
+	if err == nil || !strings.Contains(err.Error(), "into raichu it needs to use a thunder-stone (--item thunder-stone)") {
+		t.Fatalf("expected the stone to be asked for, got %v", err)
+	}
+	_, err = commandEvolve(s, "pikachu", "--item", "thunder-stone")
+	if err == nil || !strings.Contains(err.Error(), "have a thunder-stone") {
+		t.Fatalf("expected the missing stone to be reported, got %v", err)
+	}
+
+	// the stone is the prize for pikachu's fifth win
+	s.stats.BattlesWon = battlesPerPrize - 1
+	if _, err := commandBattle(s, "pikachu", "magikarp", "--level", "5"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := commandEvolve(s, "pikachu", "--item", "thunder-stone"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.caught[1].Name != "raichu" || s.inventory["thunder-stone"] != 0 {
+		t.Errorf("expected raichu and the stone used up, got %v and %d stones", s.caught, s.inventory["thunder-stone"])
+	}
+	if _, err := commandEvolve(s, "raichu"); err == nil || err.Error() != "raichu does not evolve" {
+		t.Errorf("expected raichu to be fully evolved, got %v", err)
+	}
+}
+
+func TestUnmetConditions(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.now = func() time.Time { return time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC) }
+	pokemon := caughtPokemon{Name: "eevee", Level: 10, Friendship: 230}
+	cases := []struct {
+		name     string
+		detail   pokeEvolutionDetail
+		opts     evolveOptions
+		expected string
+	}{
+		{"friendship", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "level-up"}, MinHappiness: 220, TimeOfDay: "night"}, evolveOptions{}, ""},
+		{"daytime", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "level-up"}, MinHappiness: 220, TimeOfDay: "day"}, evolveOptions{}, "evolve during the day"},
+		{"trade", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "trade"}}, evolveOptions{}, "be traded (--trade)"},
+		{"traded", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "trade"}}, evolveOptions{trade: true}, ""},
+		{"shed", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "shed"}}, evolveOptions{}, "shed, which this pokedex can not do"},
+		{"unfriendly", pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "level-up"}, MinHappiness: 240}, evolveOptions{}, "reach 240 friendship (it has 230)"},
+	}
+	for _, c := range cases {
+		unmet := strings.Join(s.unmetConditions(pokemon, c.detail, c.opts), ", ")
+		if unmet != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, unmet)
+		}
+	}
+}
+
+func TestUnmetGender(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	female, male := 1, 2
+	cases := []struct {
+		gender   string
+		want     *int
+		expected string
+	}{
+		{"male", &male, ""},
+		{"female", &male, "be male"},
+		{"female", &female, ""},
+		{"", &female, "be female"},
+		{"", nil, ""},
+	}
+	for _, c := range cases {
+		pokemon := caughtPokemon{Name: "kirlia", Level: 30, Gender: c.gender}
+		detail := pokeEvolutionDetail{Trigger: pokeNamedResource{Name: "use-item"}, Gender: c.want}
+		unmet := strings.Join(s.unmetConditions(pokemon, detail, evolveOptions{}), ", ")
+		if unmet != c.expected {
+			t.Errorf("%q pokemon: expected %q, got %q", c.gender, c.expected, unmet)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	// maxIV is the highest individual value a stat can have
+	maxIV = 31
+	// maxNicknameLength is how long a nickname the games allow
+	maxNicknameLength = 12
+)
+
+// statNames are the stats every pokemon has, in the order the games list them
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// nature raises one stat by 10% and lowers another by 10%, natures that
+// would raise and lower the same stat change nothing
+type nature struct {
+	name      string
+	increased string
+	decreased string
+}
+
+// natures are the 25 natures in the games' order
+var natures = []nature{
+	{"hardy", "attack", "attack"},
+	{"lonely", "attack", "defense"},
+	{"brave", "attack", "speed"},
+	{"adamant", "attack", "special-attack"},
+	{"naughty", "attack", "special-defense"},
+	{"bold", "defense", "attack"},
+	{"docile", "defense", "defense"},
+	{"relaxed", "defense", "speed"},
+	{"impish", "defense", "special-attack"},
+	{"lax", "defense", "special-defense"},
+	{"timid", "speed", "attack"},
+	{"hasty", "speed", "defense"},
+	{"serious", "speed", "speed"},
+	{"jolly", "speed", "special-attack"},
+	{"naive", "speed", "special-defense"},
+	{"modest", "special-attack", "attack"},
+	{"mild", "special-attack", "defense"},
+	{"quiet", "special-attack", "speed"},
+	{"bashful", "special-attack", "special-attack"},
+	{"rash", "special-attack", "special-defense"},
+	{"calm", "special-defense", "attack"},
+	{"gentle", "special-defense", "defense"},
+	{"sassy", "special-defense", "speed"},
+	{"careful", "special-defense", "special-attack"},
+	{"quirky", "special-defense", "special-defense"},
+}
+
+func findNature(name string) (nature, bool) {
+	for _, n := range natures {
+		if n.name == name {
+			return n, true
+		}
+	}
+	return nature{}, false
+}
+
+// describeNature names a nature with the stats it changes, e.g.
+// "adamant (+attack, -special-attack)"
+func describeNature(name string) string {
+	n, found := findNature(name)
+	if !found || n.increased == n.decreased {
+		return name
+	}
+	return fmt.Sprintf("%s (+%s, -%s)", n.name, n.increased, n.decreased)
+}
+
+// rollGender picks a gender the way the species' gender rate says, the
+// chance of a female in eighths or -1 for no gender at all
+func (s *session) rollGender(genderRate int) string {
+	switch {
+	case genderRate < 0:
+		return "genderless"
+	case s.rng.Intn(8) < genderRate:
+		return "female"
+	default:
+		return "male"
+	}
+}
+
+// individual rolls what sets one caught pokemon apart from the others of
+// its species: gender, nature and individual values
+func (s *session) individual(species pokeSpecies) caughtPokemon {
+	ivs := map[string]int{}
+	for _, stat := range statNames {
+		ivs[stat] = s.rng.Intn(maxIV + 1)
+	}
+	return caughtPokemon{
+		Gender: s.rollGender(species.GenderRate),
+		Nature: natures[s.rng.Intn(len(natures))].name,
+		IVs:    ivs,
+	}
+}
+
+// displayName is the pokemon's nickname, or its species name without one
+func (p caughtPokemon) displayName() string {
+	if p.Nickname != "" {
+		return p.Nickname
+	}
+	return p.Name
+}
+
+func commandNickname(s *session, params ...string) (commandResult, error) {
+	if len(params) == 0 || len(params) > 2 {
+		return nil, fmt.Errorf("nickname command requires the id of a caught pokemon, and a nickname unless you are removing it")
+	}
+	pokemon, err := s.findCaught(params[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 1 {
+		pokemon.Nickname = ""
+		s.caught[pokemon.ID] = pokemon
+		return messageResult{Message: fmt.Sprintf("#%d %s no longer has a nickname", pokemon.ID, pokemon.Name)}, nil
+	}
+
+	nickname := params[1]
+	if utf8.RuneCountInString(nickname) > maxNicknameLength {
+		return nil, fmt.Errorf("nicknames can be at most %d characters long", maxNicknameLength)
+	}
+	// a number would be taken for an id wherever the pokemon is looked up
+	if _, err := strconv.Atoi(nickname); err == nil {
+		return nil, fmt.Errorf("a nickname can not be a number")
+	}
+	pokemon.Nickname = nickname
+	s.caught[pokemon.ID] = pokemon
+	return messageResult{Message: fmt.Sprintf("#%d %s is now called %s", pokemon.ID, pokemon.Name, nickname)}, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRollGender(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	cases := map[int]string{-1: "genderless", 0: "male", 8: "female"}
+	for rate, expected := range cases {
+		for i := 0; i < 20; i++ {
+			if gender := s.rollGender(rate); gender != expected {
+				t.Fatalf("expected a gender rate of %d to always give %s, got %s", rate, expected, gender)
+			}
+		}
+	}
+}
+
+func TestIndividual(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	pokemon := s.individual(pokeSpecies{GenderRate: 4})
+	if _, found := findNature(pokemon.Nature); !found {
+		t.Errorf("expected one of the 25 natures, got %q", pokemon.Nature)
+	}
+	if len(pokemon.IVs) != len(statNames) {
+		t.Fatalf("expected an iv for every stat, got %v", pokemon.IVs)
+	}
+	for stat, iv := range pokemon.IVs {
+		if iv < 0 || iv > maxIV {
+			t.Errorf("expected the %s iv to be from 0 to %d, got %d", stat, maxIV, iv)
+		}
+	}
+}
+
+func TestDescribeNature(t *testing.T) {
+	cases := map[string]string{
+		"adamant": "adamant (+attack, -special-attack)",
+		"hardy":   "hardy",
+		"unknown": "unknown",
+	}
+	for name, expected := range cases {
+		if description := describeNature(name); description != expected {
+			t.Errorf("expected %q, got %q", expected, description)
+		}
+	}
+}
+
+func TestCatchRecordsIndividual(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.location = "canalave-city-area"
+	if err := s.runLine("catch magikarp --ball master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pokemon := s.caught[1]
+	if pokemon.CaughtIn != "canalave-city-area" || pokemon.Gender == "" || pokemon.Nature == "" || len(pokemon.IVs) == 0 {
+		t.Errorf("expected the catch to record where it happened and the pokemon's attributes, got %+v", pokemon)
+	}
+}
+
+func TestNickname(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+
+	// the nickname keeps its case, and is found in any
+	for _, line := range []string{"nickname 2 Sparky", "inspect SPARKY"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s.caught[2].Nickname != "Sparky" || !strings.Contains(out.String(), "#2 pikachu is now called Sparky") || !strings.Contains(out.String(), "Nickname: Sparky\n") {
+		t.Errorf("expected the nickname as typed, got %q and %q", s.caught[2].Nickname, out.String())
+	}
+	if pokemon, err := s.findCaught("sparky"); err != nil || pokemon.ID != 2 {
+		t.Errorf("expected to find #2 by its nickname, got %+v and %v", pokemon, err)
+	}
+	if _, err := commandNickname(s, "1", "thunderstruck1"); err == nil {
+		t.Errorf("expected a nickname longer than %d characters to be refused", maxNicknameLength)
+	}
+	if _, err := commandNickname(s, "1", "ピカチュウ"); err != nil || s.caught[1].Nickname != "ピカチュウ" {
+		t.Errorf("expected a 5 character nickname to be accepted whatever its bytes, got %v", err)
+	}
+	if _, err := commandNickname(s, "1", "42"); err == nil {
+		t.Errorf("expected a number to be refused as a nickname")
+	}
+	if _, err := commandNickname(s, "2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.caught[2].Nickname != "" {
+		t.Errorf("expected the nickname to be removed, got %q", s.caught[2].Nickname)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ballType is a kind of ball that can be thrown, keyed by its pokeapi item name
+type ballType struct {
+	item     string
+	name     string
+	modifier float64
+}
+
+// ballTypes are listed from the weakest to the strongest
+var ballTypes = []ballType{
+	{item: "poke-ball", name: "Pokeball", modifier: 1},
+	{item: "great-ball", name: "Great Ball", modifier: 1.5},
+	{item: "ultra-ball", name: "Ultra Ball", modifier: 2},
+	{item: "master-ball", name: "Master Ball", modifier: 255},
+}
+
+// startingInventory is what a new trainer sets out with
+func startingInventory() map[string]int {
+	return map[string]int{
+		"poke-ball":   20,
+		"great-ball":  5,
+		"ultra-ball":  2,
+		"master-ball": 1,
+	}
+}
+
+// findBall accepts a ball by its short name (ultra) or item name (ultra-ball)
+func findBall(name string) (ballType, error) {
+	item := strings.TrimSuffix(name, "-ball") + "-ball"
+	if name == "poke" || name == "pokeball" {
+		item = "poke-ball"
+	}
+	for _, ball := range ballTypes {
+		if ball.item == item {
+			return ball, nil
+		}
+	}
+	return ballType{}, fmt.Errorf("unknown ball %q (expected poke, great, ultra or master)", name)
+}
+
+// useBall takes a ball out of the inventory
+func (s *session) useBall(ball ballType) error {
+	if s.inventory[ball.item] <= 0 {
+		return fmt.Errorf("you have no %ss left", ball.name)
+	}
+	s.inventory[ball.item]--
+	return nil
+}
+
+type inventoryItem struct {
+	Item  string `json:"item"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type inventoryResult struct {
+	Items []inventoryItem `json:"items"`
+}
+
+func (r inventoryResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, "Inventory:")
+	for _, item := range r.Items {
+		fmt.Fprintf(w, " - %s: %d\n", item.Name, item.Count)
+	}
+}
+
+func (r inventoryResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, item := range r.Items {
+		rows = append(rows, []string{item.Item, item.Name, strconv.Itoa(item.Count)})
+	}
+	return []string{"ITEM", "NAME", "COUNT"}, rows
+}
+
+func commandInventory(s *session, params ...string) (commandResult, error) {
+	if strings.Join(params, "") != "" {
+		return nil, fmt.Errorf("inventory command does not take any parameters")
+	}
+	result := inventoryResult{Items: []inventoryItem{}}
+	for _, ball := range ballTypes {
+		result.Items = append(result.Items, inventoryItem{Item: ball.item, Name: ball.name, Count: s.inventory[ball.item]})
+	}
+	// anything else, such as evolution stones, is listed once there is some
+	for _, item := range sortedKeys(s.inventory) {
+		if _, err := findBall(item); err == nil || s.inventory[item] <= 0 {
+			continue
+		}
+		result.Items = append(result.Items, inventoryItem{Item: item, Name: item, Count: s.inventory[item]})
+	}
+	return result, nil
+}
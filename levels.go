@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	maxLevel      = 100
+	maxFriendship = 255
+)
+
+// pokeGrowthRate is how much experience a species needs for every level
+type pokeGrowthRate struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Formula string `json:"formula"`
+	Levels  []struct {
+		Level      int `json:"level"`
+		Experience int `json:"experience"`
+	} `json:"levels"`
+}
+
+func (c *pokeClient) getGrowthRate(name string) (pokeGrowthRate, error) {
+	var rate pokeGrowthRate
+	err := c.get(c.resourceUrl("growth-rate", name), &rate)
+	return rate, err
+}
+
+// experienceAt is the total experience a pokemon has on reaching a level
+func (r pokeGrowthRate) experienceAt(level int) int {
+	for _, entry := range r.Levels {
+		if entry.Level == level {
+			return entry.Experience
+		}
+	}
+	return 0
+}
+
+// levelAt is the level a pokemon with some total experience has reached
+func (r pokeGrowthRate) levelAt(experience int) int {
+	level := 1
+	for _, entry := range r.Levels {
+		if entry.Experience <= experience && entry.Level > level {
+			level = entry.Level
+		}
+	}
+	return level
+}
+
+// experienceYield is the experience for defeating or catching a wild
+// pokemon, the generation i-iv formula b*L/7
+func experienceYield(baseExperience, level int) int {
+	return max(1, baseExperience*level/7)
+}
+
+// growthRate looks up the species of a pokemon and its growth curve
+func (s *session) growthRate(name string) (pokeSpecies, pokeGrowthRate, error) {
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return pokeSpecies{}, pokeGrowthRate{}, err
+	}
+	species, err := s.client.getSpecies(pokemon.Species.Name)
+	if err != nil {
+		return pokeSpecies{}, pokeGrowthRate{}, err
+	}
+	rate, err := s.client.getGrowthRate(species.GrowthRate.Name)
+	return species, rate, err
+}
+
+// level is the pokemon's level, pokemon caught before levels were tracked
+// count as caught at the default wild level
+func (p caughtPokemon) level() int {
+	if p.Level == 0 {
+		return defaultWildLevel
+	}
+	return p.Level
+}
+
+// normalize fills in the level, experience and friendship of pokemon caught
+// before they were tracked, and keeps the experience in step with the level.
+// friendship only grows, so none means it was never set
+func (p *caughtPokemon) normalize(species pokeSpecies, rate pokeGrowthRate) {
+	p.Level = p.level()
+	p.Experience = max(p.Experience, rate.experienceAt(p.Level))
+	if p.Friendship == 0 {
+		p.Friendship = species.BaseHappiness
+	}
+}
+
+// levelProgress is where a pokemon stands on its growth curve
+type levelProgress struct {
+	Level      int `json:"level"`
+	Experience int `json:"experience"`
+	// NextLevel is the total experience needed for the next level, zero
+	// once the pokemon is at the level cap
+	NextLevel int `json:"next_level,omitempty"`
+}
+
+func newLevelProgress(p caughtPokemon, rate pokeGrowthRate) levelProgress {
+	progress := levelProgress{Level: p.Level, Experience: p.Experience}
+	if p.Level < maxLevel {
+		progress.NextLevel = rate.experienceAt(p.Level + 1)
+	}
+	return progress
+}
+
+func (p levelProgress) String() string {
+	if p.NextLevel == 0 {
+		return fmt.Sprintf("%d (%d exp, max level)", p.Level, p.Experience)
+	}
+	return fmt.Sprintf("%d (%d/%d exp to level %d)", p.Level, p.Experience, p.NextLevel, p.Level+1)
+}
+
+// experienceGain is what a pokemon got out of earning experience
+type experienceGain struct {
+	Pokemon    string `json:"pokemon"`
+	Experience int    `json:"experience"`
+	FromLevel  int    `json:"from_level"`
+	ToLevel    int    `json:"to_level"`
+}
+
+func (g experienceGain) renderText(w io.Writer) {
+	fmt.Fprintf(w, "%s gained %d exp\n", g.Pokemon, g.Experience)
+	if g.ToLevel > g.FromLevel {
+		fmt.Fprintf(w, "%s grew to level %d!\n", g.Pokemon, g.ToLevel)
+	}
+}
+
+// gainExperience adds experience to a caught pokemon, raising its level
+// along its growth curve
+func (s *session) gainExperience(id int, amount int) (experienceGain, error) {
+	caught, exists := s.caught[id]
+	if !exists {
+		return experienceGain{}, fmt.Errorf("you have no pokemon with id %d", id)
+	}
+	species, rate, err := s.growthRate(caught.Name)
+	if err != nil {
+		return experienceGain{}, err
+	}
+	caught.normalize(species, rate)
+	from := caught.Level
+	caught.Experience += amount
+	caught.Level = min(maxLevel, max(caught.Level, rate.levelAt(caught.Experience)))
+	for level := from; level < caught.Level; level++ {
+		caught.Friendship = min(maxFriendship, caught.Friendship+friendshipGain(caught.Friendship))
+	}
+	s.caught[id] = caught
+	return experienceGain{Pokemon: caught.Name, Experience: amount, FromLevel: from, ToLevel: caught.Level}, nil
+}
+
+// friendshipGain is how much friendship a level up adds, less the closer a
+// pokemon already is to its trainer
+func friendshipGain(friendship int) int {
+	switch {
+	case friendship < 100:
+		return 5
+	case friendship < 200:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// rewardLead hands the experience for beating or catching a wild pokemon
+// to the first pokemon in the trainer's party, if there is one
+func (s *session) rewardLead(defeated pokePokemon, level int) error {
+	lead, exists := s.lead()
+	if !exists {
+		return nil
+	}
+	gain, err := s.gainExperience(lead.ID, experienceYield(defeated.BaseExperience, level))
+	if err != nil {
+		return err
+	}
+	if s.output == formatText {
+		gain.renderText(s.out)
+	}
+	return nil
+}
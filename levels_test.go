@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGrowthRate(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	rate, err := s.client.getGrowthRate("medium")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		experience, level int
+	}{
+		{0, 1},
+		{7, 1},
+		{8, 2},
+		{1330, 10},
+		{1331, 11},
+		{2000000, 100},
+	}
+	for _, c := range cases {
+		if level := rate.levelAt(c.experience); level != c.level {
+			t.Errorf("%d exp: expected level %d, got %d", c.experience, c.level, level)
+		}
+	}
+	if experience := rate.experienceAt(10); experience != 1000 {
+		t.Errorf("expected 1000 exp at level 10, got %d", experience)
+	}
+}
+
+func TestGainExperience(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	gain, err := s.gainExperience(1, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedGain := experienceGain{Pokemon: "pikachu", Experience: 1000, FromLevel: 10, ToLevel: 12}
+	if gain != expectedGain {
+		t.Errorf("expected %+v, got %+v", expectedGain, gain)
+	}
+	pikachu := s.caught[1]
+	if pikachu.Level != 12 || pikachu.Experience != 2000 {
+		t.Errorf("expected level 12 with 2000 exp, got level %d with %d exp", pikachu.Level, pikachu.Experience)
+	}
+	// pikachu was stored without friendship, it starts from its species'
+	// base happiness of 50 and gains 5 for each level
+	if pikachu.Friendship != 60 {
+		t.Errorf("expected 60 friendship, got %d", pikachu.Friendship)
+	}
+	if _, err := s.gainExperience(2, 10); err == nil {
+		t.Errorf("expected an error for a pokemon that was not caught")
+	}
+}
+
+func TestCatchRewardsLead(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	s.inventory["master-ball"] = 2
+	for _, line := range []string{"catch pikachu --ball master --level 5", "catch magikarp --ball master --level 14"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lead, _ := s.lead(); lead.Name != "pikachu" {
+		t.Errorf("expected the first catch to become the lead, got %q", lead.Name)
+	}
+	// magikarp yields 40*14/7 = 80 exp, taking pikachu from 125 to 205
+	if pikachu := s.caught[1]; pikachu.Level != 5 || pikachu.Experience != 205 {
+		t.Errorf("expected pikachu at level 5 with 205 exp, got level %d with %d exp", pikachu.Level, pikachu.Experience)
+	}
+	if magikarp := s.caught[2]; magikarp.Level != 14 || magikarp.Experience != 3430 {
+		t.Errorf("expected magikarp at level 14 with 3430 exp, got level %d with %d exp", magikarp.Level, magikarp.Experience)
+	}
+	if !strings.Contains(out.String(), "pikachu gained 80 exp") {
+		t.Errorf("expected the lead to gain exp, got %q", out.String())
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+)
+
+// checkCatchable makes sure a pokemon lives in the area the trainer is in,
+// unless the session is in sandbox mode where anything can be caught anywhere
+func (s *session) checkCatchable(pokemonName string) error {
+	if s.sandbox {
+		return nil
+	}
+	if s.location == "" {
+		return fmt.Errorf("you are not in any area yet, explore or travel to one first (or set sandbox on)")
+	}
+	area, err := s.client.getLocationArea(s.location)
+	if err != nil {
+		return err
+	}
+	for _, encounter := range area.PokemonEncounters {
+		if encounter.Pokemon.Name == pokemonName {
+			return nil
+		}
+	}
+	return fmt.Errorf("there is no %s in %s", pokemonName, s.location)
+}
+
+func commandTravel(s *session, params ...string) (commandResult, error) {
+	if len(params) > 1 {
+		return nil, fmt.Errorf("travel command only takes one parameter")
+	}
+	if len(params) == 0 {
+		if s.location == "" {
+			return messageResult{Message: "You are not in any area yet"}, nil
+		}
+		return messageResult{Message: fmt.Sprintf("You are in %s", s.location)}, nil
+	}
+
+	area, err := s.client.getLocationArea(params[0])
+	if err != nil {
+		return nil, err
+	}
+	s.location = area.Name
+	s.wild = nil
+	return messageResult{Message: fmt.Sprintf("You travelled to %s", area.Name)}, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatchIsLimitedToTheCurrentArea(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	s.sandbox = false
+
+	lines := []string{
+		"catch magikarp",
+		"travel canalave-city-area",
+		"catch pikachu",
+		"catch magikarp --ball master",
+		"travel",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, expected := range []string{"you are not in any area yet", "there is no pikachu in canalave-city-area"} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, errOut.String())
+		}
+	}
+	for _, expected := range []string{"You travelled to canalave-city-area", "magikarp was caught!", "You are in canalave-city-area"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	}
+	if _, seen := s.pokedex.entry("pikachu"); seen {
+		t.Errorf("expected a blocked catch to not mark the pokemon as seen")
+	}
+	if s.inventory["poke-ball"] != 20 {
+		t.Errorf("expected blocked catches to not use up balls")
+	}
+
+	s.inventory["master-ball"] = 1
+	if err := s.runLine("set sandbox on; catch pikachu --ball master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "pikachu was caught!") {
+		t.Errorf("expected sandbox mode to allow catching anywhere, got %q", out.String())
+	}
+}
+
+func TestExploreSetsTheLocation(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	if err := s.runLine("explore canalave-city-area"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.location != "canalave-city-area" {
+		t.Errorf("expected explore to move to the area, got %q", s.location)
+	}
+}
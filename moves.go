@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+type learnsetMove struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Level  int    `json:"level,omitempty"`
+}
+
+type learnsetResult struct {
+	Pokemon      string         `json:"pokemon"`
+	VersionGroup string         `json:"version_group"`
+	Moves        []learnsetMove `json:"moves"`
+}
+
+func (r learnsetResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Moves %s learns in %s:\n", r.Pokemon, r.VersionGroup)
+	if len(r.Moves) == 0 {
+		fmt.Fprintln(w, " none")
+		return
+	}
+	for _, move := range r.Moves {
+		if move.Method == "level-up" {
+			fmt.Fprintf(w, " - %s (level %d)\n", move.Name, move.Level)
+			continue
+		}
+		fmt.Fprintf(w, " - %s (%s)\n", move.Name, move.Method)
+	}
+}
+
+func (r learnsetResult) pipeValues() []string {
+	names := []string{}
+	for _, move := range r.Moves {
+		names = append(names, move.Name)
+	}
+	return names
+}
+
+func (r learnsetResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, move := range r.Moves {
+		level := ""
+		if move.Method == "level-up" {
+			level = strconv.Itoa(move.Level)
+		}
+		rows = append(rows, []string{move.Name, move.Method, level})
+	}
+	return []string{"MOVE", "METHOD", "LEVEL"}, rows
+}
+
+// latestVersionGroup finds the newest version group a pokemon has moves in,
+// going by the id at the end of the group's url
+func latestVersionGroup(pokemon pokePokemon) string {
+	latest, latestID := "", 0
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if id := resourceID(detail.VersionGroup.URL); id > latestID {
+				latest, latestID = detail.VersionGroup.Name, id
+			}
+		}
+	}
+	return latest
+}
+
+// learnset lists the moves a pokemon learns in a version group, level-up
+// moves in the order they are learned and the rest by method and name
+func learnset(pokemon pokePokemon, versionGroup, method string) []learnsetMove {
+	moves := []learnsetMove{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && detail.MoveLearnMethod.Name != method {
+				continue
+			}
+			moves = append(moves, learnsetMove{
+				Name:   move.Move.Name,
+				Method: detail.MoveLearnMethod.Name,
+				Level:  detail.LevelLearnedAt,
+			})
+		}
+	}
+	sort.SliceStable(moves, func(i, j int) bool {
+		a, b := moves[i], moves[j]
+		if (a.Method == "level-up") != (b.Method == "level-up") {
+			return a.Method == "level-up"
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.Name < b.Name
+	})
+	return moves
+}
+
+func commandLearnset(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"method"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("learnset command requires a pokemon name")
+	}
+
+	pokemon, err := s.client.getPokemon(args[0])
+	if err != nil {
+		return nil, err
+	}
+	versionGroup := s.versionGroup
+	if versionGroup == "" {
+		versionGroup = latestVersionGroup(pokemon)
+	}
+	return learnsetResult{
+		Pokemon:      pokemon.Name,
+		VersionGroup: versionGroup,
+		Moves:        learnset(pokemon, versionGroup, flags["method"]),
+	}, nil
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	formatText  outputFormat = "text"
+	formatJSON  outputFormat = "json"
+	formatYAML  outputFormat = "yaml"
+	formatTable outputFormat = "table"
+)
+
+// commandResult is the structured value a command hands back to the repl.
+// json and yaml are produced from the exported fields, text and table are
+// rendered by the result itself
+type commandResult interface {
+	renderText(w io.Writer)
+	tableRows() (headers []string, rows [][]string)
+}
+
+func parseOutputFormat(name string) (outputFormat, error) {
+	switch format := outputFormat(strings.ToLower(name)); format {
+	case formatText, formatJSON, formatYAML, formatTable:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected text, json, yaml or table)", name)
+}
+
+func renderResult(w io.Writer, format outputFormat, res commandResult) error {
+	switch format {
+	case formatJSON:
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case formatYAML:
+		data, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		return writeYAML(w, data)
+	case formatTable:
+		headers, rows := res.tableRows()
+		return writeTable(w, headers, rows)
+	default:
+		res.renderText(w)
+		return nil
+	}
+}
+
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if len(headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// fieldRows is a helper for results that describe a single record rather
+// than a list, the table is rendered as field/value pairs
+func fieldRows(pairs ...string) ([]string, [][]string) {
+	rows := [][]string{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rows = append(rows, []string{pairs[i], pairs[i+1]})
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+// messageResult is used by commands that only have a line of text to report
+type messageResult struct {
+	Message string `json:"message"`
+}
+
+func (r messageResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, r.Message)
+}
+
+func (r messageResult) tableRows() ([]string, [][]string) {
+	return []string{"MESSAGE"}, [][]string{{r.Message}}
+}
+
+// yaml output is produced from the json encoding of a result so that the
+// json struct tags are the single source of field names and ordering.
+// the json is walked as a token stream to keep the key order intact
+type yamlNode struct {
+	scalar   string
+	isString bool
+	isMap    bool
+	isList   bool
+	keys     []string
+	children []*yamlNode
+}
+
+func writeYAML(w io.Writer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := readYAMLNode(dec)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if root.isMap || root.isList {
+		if len(root.children) == 0 {
+			buf.WriteString(emptyYAML(root) + "\n")
+		} else {
+			emitYAML(&buf, root, 0)
+		}
+	} else {
+		buf.WriteString(yamlScalar(root) + "\n")
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func readYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch val := tok.(type) {
+	case json.Delim:
+		node := &yamlNode{isMap: val == '{', isList: val == '['}
+		for dec.More() {
+			if node.isMap {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, keyTok.(string))
+			}
+			child, err := readYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+		// consume the closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yamlNode{scalar: val, isString: true}, nil
+	case json.Number:
+		return &yamlNode{scalar: val.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(val)}, nil
+	default:
+		return &yamlNode{scalar: "null"}, nil
+	}
+}
+
+func emitYAML(buf *bytes.Buffer, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, child := range node.children {
+		prefix := pad + "- "
+		if node.isMap {
+			prefix = pad + yamlQuote(node.keys[i]) + ":"
+		}
+		switch {
+		case (child.isMap || child.isList) && len(child.children) == 0:
+			buf.WriteString(strings.TrimRight(prefix, " ") + " " + emptyYAML(child) + "\n")
+		case child.isMap && node.isList:
+			// the first key of a map inside a list shares the dash line
+			var nested bytes.Buffer
+			emitYAML(&nested, child, indent+2)
+			buf.WriteString(prefix + strings.TrimPrefix(nested.String(), pad+"  "))
+		case child.isMap || child.isList:
+			buf.WriteString(strings.TrimRight(prefix, " ") + "\n")
+			emitYAML(buf, child, indent+2)
+		default:
+			buf.WriteString(strings.TrimRight(prefix, " ") + " " + yamlScalar(child) + "\n")
+		}
+	}
+}
+
+func emptyYAML(node *yamlNode) string {
+	if node.isMap {
+		return "{}"
+	}
+	return "[]"
+}
+
+func yamlScalar(node *yamlNode) string {
+	if !node.isString {
+		return node.scalar
+	}
+	return yamlQuote(node.scalar)
+}
+
+// yamlQuote leaves plain strings alone and falls back to a double quoted
+// string (json quoting is valid yaml) whenever the plain form would be
+// read back as something else
+func yamlQuote(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~", "y", "n":
+		return strconv.Quote(s)
+	}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// plural formats a count with a noun, "1 attempt" or "3 attempts"
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
+
+// sortedKeys returns the keys of a map in a stable order for rendering
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRenderResult(t *testing.T) {
+	caughtAt := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	result := inspectResult{
+		Name:      "pikachu",
+		Status:    "caught",
+		FirstSeen: caughtAt,
+		CaughtAt:  &caughtAt,
+		Attempts:  2,
+		Height:    4,
+		Weight:    60,
+		Stats:     []statValue{{Name: "hp", Value: 35}},
+		Types:     []string{"electric"},
+	}
+	cases := []struct {
+		format   outputFormat
+		expected string
+	}{
+		{
+			format:   formatText,
+			expected: "Name: pikachu\nCaught: 2026-10-19 08:00 (2 attempts)\nHeight: 4\nWeight: 60\nStats:\n\t-hp: 35\nTypes:\n\t-electric\n",
+		},
+		{
+			format:   formatJSON,
+			expected: "{\n  \"name\": \"pikachu\",\n  \"status\": \"caught\",\n  \"first_seen\": \"2026-10-19T08:00:00Z\",\n  \"caught_at\": \"2026-10-19T08:00:00Z\",\n  \"attempts\": 2,\n  \"height\": 4,\n  \"weight\": 60,\n  \"stats\": [\n    {\n      \"name\": \"hp\",\n      \"value\": 35\n    }\n  ],\n  \"types\": [\n    \"electric\"\n  ]\n}\n",
+		},
+		{
+			format:   formatYAML,
+			expected: "name: pikachu\nstatus: caught\nfirst_seen: \"2026-10-19T08:00:00Z\"\ncaught_at: \"2026-10-19T08:00:00Z\"\nattempts: 2\nheight: 4\nweight: 60\nstats:\n  - name: hp\n    value: 35\ntypes:\n  - electric\n",
+		},
+		{
+			format:   formatTable,
+			expected: "FIELD       VALUE\nname        pikachu\nstatus      caught\nfirst seen  2026-10-19 08:00\nattempts    2\ncaught      2026-10-19 08:00\nheight      4\nweight      60\nhp          35\ntypes       electric\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(string(c.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderResult(&buf, c.format, result); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != c.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", c.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestYAMLQuote(t *testing.T) {
+	cases := map[string]string{
+		"pikachu":  "pikachu",
+		"":         `""`,
+		"true":     `"true"`,
+		"12":       `"12"`,
+		"a: b":     `"a: b"`,
+		"-leading": `"-leading"`,
+	}
+	for input, expected := range cases {
+		if actual := yamlQuote(input); actual != expected {
+			t.Errorf("yamlQuote(%q): expected %s, got %s", input, expected, actual)
+		}
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	if format, err := parseOutputFormat("JSON"); err != nil || format != formatJSON {
+		t.Errorf("expected json, got %q (%v)", format, err)
+	}
+	if _, err := parseOutputFormat("xml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
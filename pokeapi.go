@@ -0,0 +1,519 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"internal/pokecache"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultBaseUrl = "https://pokeapi.co/api/v2"
+
+// pokeClient fetches resources from the pokeapi, keeping responses in a
+// cache so repeated lookups do not go back over the network
+type pokeClient struct {
+	baseUrl    string
+	httpClient *http.Client
+	cache      *pokecache.Cache
+}
+
+func newPokeClient(baseUrl string, cacheInterval time.Duration) *pokeClient {
+	return &pokeClient{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		cache:      pokecache.NewCache(cacheInterval),
+	}
+}
+
+// getRaw returns the body of a url, from the cache when possible
+func (c *pokeClient) getRaw(url string) ([]byte, error) {
+	if data, hit := c.cache.Get(url); hit {
+		return data, nil
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, res.Status)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(url, data)
+	return data, nil
+}
+
+// get fetches a url and decodes the json response into target
+func (c *pokeClient) get(url string, target any) error {
+	data, err := c.getRaw(url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// resourceUrl builds the url of a named resource, e.g. pokemon/pikachu
+func (c *pokeClient) resourceUrl(resource, name string) string {
+	return fmt.Sprintf("%s/%s/%s", c.baseUrl, resource, name)
+}
+
+// listLocationAreas fetches a page of location areas, the first page when
+// pageUrl is empty
+func (c *pokeClient) listLocationAreas(pageUrl string) (pokeAreas, error) {
+	if pageUrl == "" {
+		pageUrl = c.baseUrl + "/location-area/"
+	}
+	var areas pokeAreas
+	err := c.get(pageUrl, &areas)
+	return areas, err
+}
+
+func (c *pokeClient) getLocationArea(name string) (pokeLocationArea, error) {
+	var area pokeLocationArea
+	err := c.get(c.resourceUrl("location-area", name), &area)
+	return area, err
+}
+
+func (c *pokeClient) getSpecies(name string) (pokeSpecies, error) {
+	var species pokeSpecies
+	err := c.get(c.resourceUrl("pokemon-species", name), &species)
+	return species, err
+}
+
+func (c *pokeClient) getPokemon(name string) (pokePokemon, error) {
+	var pokemon pokePokemon
+	err := c.get(c.resourceUrl("pokemon", name), &pokemon)
+	return pokemon, err
+}
+
+type pokeAreas struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"results"`
+}
+
+type pokePokemon struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	BaseExperience int    `json:"base_experience"`
+	Height         int    `json:"height"`
+	IsDefault      bool   `json:"is_default"`
+	Order          int    `json:"order"`
+	Weight         int    `json:"weight"`
+	Abilities      []struct {
+		IsHidden bool `json:"is_hidden"`
+		Slot     int  `json:"slot"`
+		Ability  struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"ability"`
+	} `json:"abilities"`
+	Forms []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"forms"`
+	GameIndices []struct {
+		GameIndex int `json:"game_index"`
+		Version   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"version"`
+	} `json:"game_indices"`
+	HeldItems []struct {
+		Item struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"item"`
+		VersionDetails []struct {
+			Rarity  int `json:"rarity"`
+			Version struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"version"`
+		} `json:"version_details"`
+	} `json:"held_items"`
+	LocationAreaEncounters string `json:"location_area_encounters"`
+	Moves                  []struct {
+		Move struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"move"`
+		VersionGroupDetails []struct {
+			LevelLearnedAt int `json:"level_learned_at"`
+			VersionGroup   struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"version_group"`
+			MoveLearnMethod struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"move_learn_method"`
+		} `json:"version_group_details"`
+	} `json:"moves"`
+	Species struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"species"`
+	Sprites struct {
+		BackDefault      string `json:"back_default"`
+		BackFemale       any    `json:"back_female"`
+		BackShiny        string `json:"back_shiny"`
+		BackShinyFemale  any    `json:"back_shiny_female"`
+		FrontDefault     string `json:"front_default"`
+		FrontFemale      any    `json:"front_female"`
+		FrontShiny       string `json:"front_shiny"`
+		FrontShinyFemale any    `json:"front_shiny_female"`
+		Other            struct {
+			DreamWorld struct {
+				FrontDefault string `json:"front_default"`
+				FrontFemale  any    `json:"front_female"`
+			} `json:"dream_world"`
+			Home struct {
+				FrontDefault     string `json:"front_default"`
+				FrontFemale      any    `json:"front_female"`
+				FrontShiny       string `json:"front_shiny"`
+				FrontShinyFemale any    `json:"front_shiny_female"`
+			} `json:"home"`
+			OfficialArtwork struct {
+				FrontDefault string `json:"front_default"`
+				FrontShiny   string `json:"front_shiny"`
+			} `json:"official-artwork"`
+			Showdown struct {
+				BackDefault      string `json:"back_default"`
+				BackFemale       any    `json:"back_female"`
+				BackShiny        string `json:"back_shiny"`
+				BackShinyFemale  any    `json:"back_shiny_female"`
+				FrontDefault     string `json:"front_default"`
+				FrontFemale      any    `json:"front_female"`
+				FrontShiny       string `json:"front_shiny"`
+				FrontShinyFemale any    `json:"front_shiny_female"`
+			} `json:"showdown"`
+		} `json:"other"`
+		Versions struct {
+			GenerationI struct {
+				RedBlue struct {
+					BackDefault  string `json:"back_default"`
+					BackGray     string `json:"back_gray"`
+					FrontDefault string `json:"front_default"`
+					FrontGray    string `json:"front_gray"`
+				} `json:"red-blue"`
+				Yellow struct {
+					BackDefault  string `json:"back_default"`
+					BackGray     string `json:"back_gray"`
+					FrontDefault string `json:"front_default"`
+					FrontGray    string `json:"front_gray"`
+				} `json:"yellow"`
+			} `json:"generation-i"`
+			GenerationIi struct {
+				Crystal struct {
+					BackDefault  string `json:"back_default"`
+					BackShiny    string `json:"back_shiny"`
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"crystal"`
+				Gold struct {
+					BackDefault  string `json:"back_default"`
+					BackShiny    string `json:"back_shiny"`
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"gold"`
+				Silver struct {
+					BackDefault  string `json:"back_default"`
+					BackShiny    string `json:"back_shiny"`
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"silver"`
+			} `json:"generation-ii"`
+			GenerationIii struct {
+				Emerald struct {
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"emerald"`
+				FireredLeafgreen struct {
+					BackDefault  string `json:"back_default"`
+					BackShiny    string `json:"back_shiny"`
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"firered-leafgreen"`
+				RubySapphire struct {
+					BackDefault  string `json:"back_default"`
+					BackShiny    string `json:"back_shiny"`
+					FrontDefault string `json:"front_default"`
+					FrontShiny   string `json:"front_shiny"`
+				} `json:"ruby-sapphire"`
+			} `json:"generation-iii"`
+			GenerationIv struct {
+				DiamondPearl struct {
+					BackDefault      string `json:"back_default"`
+					BackFemale       any    `json:"back_female"`
+					BackShiny        string `json:"back_shiny"`
+					BackShinyFemale  any    `json:"back_shiny_female"`
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"diamond-pearl"`
+				HeartgoldSoulsilver struct {
+					BackDefault      string `json:"back_default"`
+					BackFemale       any    `json:"back_female"`
+					BackShiny        string `json:"back_shiny"`
+					BackShinyFemale  any    `json:"back_shiny_female"`
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"heartgold-soulsilver"`
+				Platinum struct {
+					BackDefault      string `json:"back_default"`
+					BackFemale       any    `json:"back_female"`
+					BackShiny        string `json:"back_shiny"`
+					BackShinyFemale  any    `json:"back_shiny_female"`
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"platinum"`
+			} `json:"generation-iv"`
+			GenerationV struct {
+				BlackWhite struct {
+					Animated struct {
+						BackDefault      string `json:"back_default"`
+						BackFemale       any    `json:"back_female"`
+						BackShiny        string `json:"back_shiny"`
+						BackShinyFemale  any    `json:"back_shiny_female"`
+						FrontDefault     string `json:"front_default"`
+						FrontFemale      any    `json:"front_female"`
+						FrontShiny       string `json:"front_shiny"`
+						FrontShinyFemale any    `json:"front_shiny_female"`
+					} `json:"animated"`
+					BackDefault      string `json:"back_default"`
+					BackFemale       any    `json:"back_female"`
+					BackShiny        string `json:"back_shiny"`
+					BackShinyFemale  any    `json:"back_shiny_female"`
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"black-white"`
+			} `json:"generation-v"`
+			GenerationVi struct {
+				OmegarubyAlphasapphire struct {
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"omegaruby-alphasapphire"`
+				XY struct {
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"x-y"`
+			} `json:"generation-vi"`
+			GenerationVii struct {
+				Icons struct {
+					FrontDefault string `json:"front_default"`
+					FrontFemale  any    `json:"front_female"`
+				} `json:"icons"`
+				UltraSunUltraMoon struct {
+					FrontDefault     string `json:"front_default"`
+					FrontFemale      any    `json:"front_female"`
+					FrontShiny       string `json:"front_shiny"`
+					FrontShinyFemale any    `json:"front_shiny_female"`
+				} `json:"ultra-sun-ultra-moon"`
+			} `json:"generation-vii"`
+			GenerationViii struct {
+				Icons struct {
+					FrontDefault string `json:"front_default"`
+					FrontFemale  any    `json:"front_female"`
+				} `json:"icons"`
+			} `json:"generation-viii"`
+		} `json:"versions"`
+	} `json:"sprites"`
+	Cries struct {
+		Latest string `json:"latest"`
+		Legacy string `json:"legacy"`
+	} `json:"cries"`
+	Stats []struct {
+		BaseStat int `json:"base_stat"`
+		Effort   int `json:"effort"`
+		Stat     struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"stat"`
+	} `json:"stats"`
+	Types []struct {
+		Slot int `json:"slot"`
+		Type struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"type"`
+	} `json:"types"`
+	PastTypes []struct {
+		Generation struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"generation"`
+		Types []struct {
+			Slot int `json:"slot"`
+			Type struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"type"`
+		} `json:"types"`
+	} `json:"past_types"`
+}
+
+type pokeLocationArea struct {
+	EncounterMethodRates []struct {
+		EncounterMethod struct {
+			Name string `json:"name"`
+			Url  string `json:"url"`
+		} `json:"encounter_method"`
+		VersionDetails []struct {
+			Rate    int `json:"rate"`
+			Version struct {
+				Name string `json:"name"`
+				Url  string `json:"url"`
+			} `json:"version"`
+		} `json:"version_details"`
+	} `json:"encounter_method_rates"`
+	GameIndex int `json:"game_index"`
+	Id        int `json:"id"`
+	Location  struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"location"`
+	Name  string `json:"name"`
+	Names []struct {
+		Language struct {
+			Name string `json:"name"`
+			Url  string `json:"url"`
+		} `json:"language"`
+		Name string `json:"name"`
+	} `json:"names"`
+	PokemonEncounters []struct {
+		Pokemon struct {
+			Name string `json:"name"`
+			Url  string `json:"url"`
+		} `json:"pokemon"`
+		VersionDetails []struct {
+			EncounterDetails []struct {
+				Chance          int `json:"chance"`
+				ConditionValues []struct {
+					Name string `json:"name"`
+					Url  string `json:"url"`
+				} `json:"condition_values"`
+				MaxLevel int `json:"max_level"`
+				Method   struct {
+					Name string `json:"name"`
+					Url  string `json:"url"`
+				} `json:"method"`
+				MinLevel int `json:"min_level"`
+			} `json:"encounter_details"`
+			MaxChance int `json:"max_chance"`
+			Version   struct {
+				Name string `json:"name"`
+				Url  string `json:"url"`
+			} `json:"version"`
+		} `json:"version_details"`
+	} `json:"pokemon_encounters"`
+}
+
+// baseStat returns the base value of a stat such as "hp" or "speed"
+func (p pokePokemon) baseStat(name string) int {
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == name {
+			return stat.BaseStat
+		}
+	}
+	return 0
+}
+
+type pokeSpecies struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	CaptureRate int    `json:"capture_rate"`
+	// GenderRate is the chance of a pokemon being female in eighths, -1
+	// for species without a gender
+	GenderRate int `json:"gender_rate"`
+	GrowthRate struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"growth_rate"`
+	BaseHappiness int                 `json:"base_happiness"`
+	IsLegendary   bool                `json:"is_legendary"`
+	IsMythical    bool                `json:"is_mythical"`
+	Color         pokeNamedResource   `json:"color"`
+	Shape         *pokeNamedResource  `json:"shape"`
+	Habitat       *pokeNamedResource  `json:"habitat"`
+	Generation    pokeNamedResource   `json:"generation"`
+	EggGroups     []pokeNamedResource `json:"egg_groups"`
+	Genera        []struct {
+		Genus    string            `json:"genus"`
+		Language pokeNamedResource `json:"language"`
+	} `json:"genera"`
+	EvolvesFromSpecies *pokeNamedResource `json:"evolves_from_species"`
+	EvolutionChain     struct {
+		Url string `json:"url"`
+	} `json:"evolution_chain"`
+	Varieties []struct {
+		IsDefault bool              `json:"is_default"`
+		Pokemon   pokeNamedResource `json:"pokemon"`
+	} `json:"varieties"`
+	FlavorTextEntries []struct {
+		FlavorText string            `json:"flavor_text"`
+		Language   pokeNamedResource `json:"language"`
+		Version    pokeNamedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+}
+
+// pokeNamedResource is the api's link to another resource
+type pokeNamedResource struct {
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// defaultPokemon is the name of the pokemon a species is usually met as,
+// most species have just the one variety named after them
+func (s pokeSpecies) defaultPokemon() string {
+	for _, variety := range s.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return s.Name
+}
+
+// flavorText is the english pokedex entry of a species in a game version,
+// the newest one when the version has none or none is given. the api keeps
+// the games' line breaks and page feeds, which are turned into spaces
+func (s pokeSpecies) flavorText(version string) string {
+	text := ""
+	for _, entry := range s.FlavorTextEntries {
+		if entry.Language.Name != "en" {
+			continue
+		}
+		text = entry.FlavorText
+		if entry.Version.Name == version {
+			break
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const pokedexTimeLayout = "2006-01-02 15:04"
+
+// pokedexEntry is what the trainer knows about one species. a species is
+// seen once it shows up while exploring or escapes a catch, and caught the
+// first time a catch succeeds
+type pokedexEntry struct {
+	Name      string     `json:"name"`
+	FirstSeen time.Time  `json:"first_seen"`
+	LastSeen  time.Time  `json:"last_seen"`
+	Attempts  int        `json:"attempts"`
+	CaughtAt  *time.Time `json:"caught_at,omitempty"`
+	// Shiny is set once the trainer has caught a shiny one
+	Shiny bool `json:"shiny,omitempty"`
+}
+
+func (e *pokedexEntry) caught() bool {
+	return e.CaughtAt != nil
+}
+
+func (e *pokedexEntry) status() string {
+	if e.caught() {
+		return "caught"
+	}
+	return "seen"
+}
+
+// caughtPokemon is a pokemon the trainer owns. its species data is looked
+// up from the api when it is needed rather than kept in the save file
+type caughtPokemon struct {
+	ID       int       `json:"id"`
+	Name     string    `json:"name"`
+	Nickname string    `json:"nickname,omitempty"`
+	CaughtAt time.Time `json:"caught_at"`
+	// CaughtIn is the area the pokemon was caught in, empty when it was
+	// caught by name outside of any area
+	CaughtIn   string `json:"caught_in,omitempty"`
+	Level      int    `json:"level,omitempty"`
+	Experience int    `json:"experience,omitempty"`
+	Friendship int    `json:"friendship,omitempty"`
+	// pokemon caught before individuals were tracked have no gender,
+	// nature or ivs
+	Gender string         `json:"gender,omitempty"`
+	Nature string         `json:"nature,omitempty"`
+	IVs    map[string]int `json:"ivs,omitempty"`
+	Shiny  bool           `json:"shiny,omitempty"`
+	// EvolvedFrom lists the forms the pokemon had before, oldest first
+	EvolvedFrom []string `json:"evolved_from,omitempty"`
+}
+
+type pokedex struct {
+	entries map[string]*pokedexEntry
+}
+
+func newPokedex() *pokedex {
+	return &pokedex{entries: map[string]*pokedexEntry{}}
+}
+
+// see records an encounter with a species and returns its entry
+func (p *pokedex) see(name string, at time.Time) *pokedexEntry {
+	entry, exists := p.entries[name]
+	if !exists {
+		entry = &pokedexEntry{Name: name, FirstSeen: at}
+		p.entries[name] = entry
+	}
+	entry.LastSeen = at
+	return entry
+}
+
+// recordAttempt records a thrown ball, whether or not it caught the pokemon
+func (p *pokedex) recordAttempt(name string, caught bool, at time.Time) {
+	entry := p.see(name, at)
+	entry.Attempts++
+	if caught && entry.CaughtAt == nil {
+		caughtAt := at
+		entry.CaughtAt = &caughtAt
+	}
+}
+
+// register marks a species as caught without a ball being thrown, as when
+// a caught pokemon evolves into it
+func (p *pokedex) register(name string, at time.Time) {
+	entry := p.see(name, at)
+	if entry.CaughtAt == nil {
+		caughtAt := at
+		entry.CaughtAt = &caughtAt
+	}
+}
+
+// registerShiny marks that a shiny of a species has been caught
+func (p *pokedex) registerShiny(name string) {
+	if entry, exists := p.entries[name]; exists {
+		entry.Shiny = true
+	}
+}
+
+func (p *pokedex) entry(name string) (*pokedexEntry, bool) {
+	entry, exists := p.entries[name]
+	return entry, exists
+}
+
+type statValue struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+type inspectResult struct {
+	ID        int            `json:"id,omitempty"`
+	Name      string         `json:"name"`
+	Nickname  string         `json:"nickname,omitempty"`
+	Status    string         `json:"status"`
+	FirstSeen time.Time      `json:"first_seen"`
+	CaughtAt  *time.Time     `json:"caught_at,omitempty"`
+	CaughtIn  string         `json:"caught_in,omitempty"`
+	Attempts  int            `json:"attempts"`
+	Level     *levelProgress `json:"level,omitempty"`
+	// Friendship is only set along with the level, for owned pokemon
+	Friendship  int         `json:"friendship,omitempty"`
+	EvolvedFrom []string    `json:"evolved_from,omitempty"`
+	Gender      string      `json:"gender,omitempty"`
+	Nature      string      `json:"nature,omitempty"`
+	Shiny       bool        `json:"shiny,omitempty"`
+	Height      int         `json:"height,omitempty"`
+	Weight      int         `json:"weight,omitempty"`
+	Stats       []statValue `json:"stats,omitempty"`
+	IVs         []statValue `json:"ivs,omitempty"`
+	Types       []string    `json:"types,omitempty"`
+	Sprite      string      `json:"sprite,omitempty"`
+	// Species is only looked up for pokemon the trainer owns
+	Species *speciesDetails `json:"species,omitempty"`
+	// Art is the sprite drawn in the terminal, for inspect --sprite
+	Art string `json:"-"`
+}
+
+// statText is a base stat, followed by the pokemon's individual value for
+// it when it has one
+func (r inspectResult) statText(stat statValue) string {
+	for _, iv := range r.IVs {
+		if iv.Name == stat.Name {
+			return fmt.Sprintf("%d (iv %d)", stat.Value, iv.Value)
+		}
+	}
+	return strconv.Itoa(stat.Value)
+}
+
+func (r inspectResult) renderText(w io.Writer) {
+	if r.ID != 0 {
+		fmt.Fprintf(w, "ID: %d\n", r.ID)
+	}
+	fmt.Fprintf(w, "Name: %s\n", r.Name)
+	if r.Nickname != "" {
+		fmt.Fprintf(w, "Nickname: %s\n", r.Nickname)
+	}
+	if r.CaughtAt == nil {
+		fmt.Fprintf(w, "Seen: %s\n", r.FirstSeen.Format(pokedexTimeLayout))
+		fmt.Fprintf(w, "Not caught yet (%s)\n", plural(r.Attempts, "attempt"))
+		return
+	}
+	fmt.Fprintf(w, "Caught: %s (%s)\n", r.CaughtAt.Format(pokedexTimeLayout), plural(r.Attempts, "attempt"))
+	if r.CaughtIn != "" {
+		fmt.Fprintf(w, "Caught in: %s\n", r.CaughtIn)
+	}
+	if r.Level != nil {
+		fmt.Fprintf(w, "Level: %s\n", r.Level)
+		fmt.Fprintf(w, "Friendship: %d\n", r.Friendship)
+	}
+	if len(r.EvolvedFrom) > 0 {
+		fmt.Fprintf(w, "Evolved from: %s\n", strings.Join(r.EvolvedFrom, ", "))
+	}
+	if r.Gender != "" {
+		fmt.Fprintf(w, "Gender: %s\n", r.Gender)
+	}
+	if r.Nature != "" {
+		fmt.Fprintf(w, "Nature: %s\n", describeNature(r.Nature))
+	}
+	if r.Shiny {
+		fmt.Fprintln(w, "Shiny: yes")
+	}
+	fmt.Fprintf(w, "Height: %d\n", r.Height)
+	fmt.Fprintf(w, "Weight: %d\n", r.Weight)
+	fmt.Fprintln(w, "Stats:")
+	for _, stat := range r.Stats {
+		fmt.Fprintf(w, "\t-%s: %s\n", stat.Name, r.statText(stat))
+	}
+	fmt.Fprintln(w, "Types:")
+	for _, typeName := range r.Types {
+		fmt.Fprintf(w, "\t-%s\n", typeName)
+	}
+	if r.Sprite != "" {
+		fmt.Fprintf(w, "Sprite: %s\n", r.Sprite)
+	}
+	fmt.Fprint(w, r.Art)
+	if r.Species != nil {
+		r.Species.renderText(w)
+	}
+}
+
+func (r inspectResult) tableRows() ([]string, [][]string) {
+	pairs := []string{}
+	if r.ID != 0 {
+		pairs = append(pairs, "id", strconv.Itoa(r.ID))
+	}
+	pairs = append(pairs, "name", r.Name)
+	if r.Nickname != "" {
+		pairs = append(pairs, "nickname", r.Nickname)
+	}
+	pairs = append(pairs,
+		"status", r.Status,
+		"first seen", r.FirstSeen.Format(pokedexTimeLayout),
+		"attempts", strconv.Itoa(r.Attempts),
+	)
+	if r.CaughtAt == nil {
+		return fieldRows(pairs...)
+	}
+	pairs = append(pairs,
+		"caught", r.CaughtAt.Format(pokedexTimeLayout),
+	)
+	if r.CaughtIn != "" {
+		pairs = append(pairs, "caught in", r.CaughtIn)
+	}
+	if r.Level != nil {
+		pairs = append(pairs, "level", r.Level.String(), "friendship", strconv.Itoa(r.Friendship))
+	}
+	if len(r.EvolvedFrom) > 0 {
+		pairs = append(pairs, "evolved from", strings.Join(r.EvolvedFrom, ", "))
+	}
+	if r.Gender != "" {
+		pairs = append(pairs, "gender", r.Gender)
+	}
+	if r.Nature != "" {
+		pairs = append(pairs, "nature", describeNature(r.Nature))
+	}
+	if r.Shiny {
+		pairs = append(pairs, "shiny", "yes")
+	}
+	pairs = append(pairs,
+		"height", strconv.Itoa(r.Height),
+		"weight", strconv.Itoa(r.Weight),
+	)
+	for _, stat := range r.Stats {
+		pairs = append(pairs, stat.Name, r.statText(stat))
+	}
+	pairs = append(pairs, "types", strings.Join(r.Types, ", "))
+	if r.Sprite != "" {
+		pairs = append(pairs, "sprite", r.Sprite)
+	}
+	if r.Species != nil {
+		pairs = append(pairs, r.Species.rows()...)
+	}
+	return fieldRows(pairs...)
+}
+
+type pokedexResult struct {
+	Caught  int            `json:"caught"`
+	Seen    int            `json:"seen"`
+	Entries []pokedexEntry `json:"entries"`
+}
+
+func (r pokedexResult) renderText(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Your Pokedex: %d caught, %d seen\n", r.Caught, r.Seen)
+	for _, entry := range r.Entries {
+		if entry.Shiny {
+			fmt.Fprintf(w, " - %v (%s, shiny, %s)\n", entry.Name, entry.status(), plural(entry.Attempts, "attempt"))
+			continue
+		}
+		fmt.Fprintf(w, " - %v (%s, %s)\n", entry.Name, entry.status(), plural(entry.Attempts, "attempt"))
+	}
+	fmt.Fprintln(w)
+}
+
+func (r pokedexResult) pipeValues() []string {
+	names := []string{}
+	for _, entry := range r.Entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func (r pokedexResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, entry := range r.Entries {
+		caughtAt := ""
+		if entry.CaughtAt != nil {
+			caughtAt = entry.CaughtAt.Format(pokedexTimeLayout)
+		}
+		rows = append(rows, []string{
+			entry.Name,
+			entry.status(),
+			entry.FirstSeen.Format(pokedexTimeLayout),
+			caughtAt,
+			strconv.Itoa(entry.Attempts),
+			strconv.FormatBool(entry.Shiny),
+		})
+	}
+	return []string{"POKEMON", "STATUS", "FIRST SEEN", "CAUGHT", "ATTEMPTS", "SHINY"}, rows
+}
+
+func commandInspect(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, nil, []string{"sprite"})
+	if err != nil {
+		return nil, err
+	}
+	if strings.Join(args, "") == "" {
+		return nil, fmt.Errorf("inspect command requires a pokemon name")
+	}
+
+	if len(args) > 1 {
+		return nil, fmt.Errorf("inspect command only takes one pokemon")
+	}
+
+	// an id or nickname picks one caught pokemon, a name the species'
+	// pokedex entry and the first of that pokemon the trainer owns
+	name := args[0]
+	var owned *caughtPokemon
+	if pokemon, err := s.findCaught(name); err == nil {
+		owned, name = &pokemon, pokemon.Name
+	} else if _, convErr := strconv.Atoi(name); convErr == nil {
+		return nil, err
+	} else {
+		for _, pokemon := range s.ordered() {
+			if pokemon.Name == name {
+				owned = &pokemon
+				break
+			}
+		}
+	}
+
+	entry, seen := s.pokedex.entry(name)
+	if !seen {
+		return nil, fmt.Errorf("you have not seen that pokemon")
+	}
+	result := inspectResult{
+		Name:      entry.Name,
+		Status:    entry.status(),
+		FirstSeen: entry.FirstSeen,
+		CaughtAt:  entry.CaughtAt,
+		Attempts:  entry.Attempts,
+	}
+
+	if owned == nil {
+		return result, nil
+	}
+	caught := *owned
+	result.ID = caught.ID
+	result.Nickname = caught.Nickname
+	result.CaughtIn = caught.CaughtIn
+	result.Gender = caught.Gender
+	result.Nature = caught.Nature
+	result.Shiny = caught.Shiny
+	for _, stat := range statNames {
+		if iv, exists := caught.IVs[stat]; exists {
+			result.IVs = append(result.IVs, statValue{Name: stat, Value: iv})
+		}
+	}
+	species, rate, err := s.growthRate(name)
+	if err != nil {
+		return nil, err
+	}
+	caught.normalize(species, rate)
+	progress := newLevelProgress(caught, rate)
+	result.Level = &progress
+	result.Friendship = caught.Friendship
+	result.EvolvedFrom = caught.EvolvedFrom
+	inspectedPokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return nil, err
+	}
+
+	hp := 0
+	attack := 0
+	defense := 0
+	specialAttack := 0
+	specialDefense := 0
+	speed := 0
+
+	for _, val := range inspectedPokemon.Stats {
+		switch val.Stat.Name {
+		case "hp":
+			hp = val.BaseStat
+		case "attack":
+			attack = val.BaseStat
+		case "defense":
+			defense = val.BaseStat
+		case "special-attack":
+			specialAttack = val.BaseStat
+		case "special-defense":
+			specialDefense = val.BaseStat
+		case "speed":
+			speed = val.BaseStat
+		}
+	}
+	result.Height = inspectedPokemon.Height
+	result.Weight = inspectedPokemon.Weight
+	result.Stats = []statValue{
+		{Name: "hp", Value: hp},
+		{Name: "attack", Value: attack},
+		{Name: "defense", Value: defense},
+		{Name: "special-attack", Value: specialAttack},
+		{Name: "special-defense", Value: specialDefense},
+		{Name: "speed", Value: speed},
+	}
+	result.Types = []string{}
+	for _, val := range inspectedPokemon.Types {
+		result.Types = append(result.Types, val.Type.Name)
+	}
+	result.Sprite = inspectedPokemon.spriteUrl(spriteOptions{version: s.version, versionGroup: s.versionGroup, shiny: caught.Shiny})
+	details := newSpeciesDetails(species, s.version)
+	result.Species = &details
+	if flags["sprite"] != "" && result.Sprite != "" && s.output == formatText {
+		if result.Art, err = s.spriteArt(result.Sprite, s.colors); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func commandPokedex(s *session, params ...string) (commandResult, error) {
+	filter := strings.Join(params, "")
+	if filter != "" && filter != "caught" && filter != "seen" {
+		return nil, fmt.Errorf("pokedex command takes no parameter, caught or seen")
+	}
+
+	result := pokedexResult{Entries: []pokedexEntry{}}
+	for _, name := range sortedKeys(s.pokedex.entries) {
+		entry := s.pokedex.entries[name]
+		result.Seen++
+		if entry.caught() {
+			result.Caught++
+		}
+		if filter == "" || filter == entry.status() {
+			result.Entries = append(result.Entries, *entry)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const defaultProfile = "default"
+
+var validProfileName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// trainerStats are the running totals kept for each profile
+type trainerStats struct {
+	BallsThrown   int       `json:"balls_thrown"`
+	PokemonCaught int       `json:"pokemon_caught"`
+	AreasExplored int       `json:"areas_explored"`
+	BattlesWon    int       `json:"battles_won"`
+	BattlesLost   int       `json:"battles_lost"`
+	Quiz          quizScore `json:"quiz"`
+}
+
+// profilePath is where a profile's save file lives
+func (s *session) profilePath(name string) string {
+	return filepath.Join(s.profileDir, name+".json")
+}
+
+// checkProfileName rejects names that are not a plain file name, which
+// could reach files outside the profile directory
+func checkProfileName(name string) error {
+	if !validProfileName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use lowercase letters, digits, - and _)", name)
+	}
+	return nil
+}
+
+// useProfile points the session at a profile's save file and loads it,
+// starting a fresh game when the profile has not been saved yet
+func (s *session) useProfile(name string) error {
+	if err := checkProfileName(name); err != nil {
+		return err
+	}
+	s.profile = name
+	s.savePath = s.profilePath(name)
+	s.restore(gameState{})
+	err := s.loadGame()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// migrateLegacySave moves a save file from before profiles existed into
+// the default profile
+func migrateLegacySave(legacyPath, profileDir string) error {
+	if _, err := os.Stat(legacyPath); err != nil {
+		return nil
+	}
+	target := filepath.Join(profileDir, defaultProfile+".json")
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(profileDir, 0755); err != nil {
+		return err
+	}
+	return os.Rename(legacyPath, target)
+}
+
+// profileNames lists the saved profiles plus the active one, which may not
+// have been saved yet
+func (s *session) profileNames() ([]string, error) {
+	names := map[string]bool{s.profile: true}
+	files, err := os.ReadDir(s.profileDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, file := range files {
+		if name, isSave := strings.CutSuffix(file.Name(), ".json"); isSave && !file.IsDir() {
+			names[name] = true
+		}
+	}
+	return sortedKeys(names), nil
+}
+
+type profileSummary struct {
+	Name   string       `json:"name"`
+	Active bool         `json:"active"`
+	Caught int          `json:"caught"`
+	Seen   int          `json:"seen"`
+	Stats  trainerStats `json:"stats"`
+}
+
+type profileListResult struct {
+	Profiles []profileSummary `json:"profiles"`
+}
+
+func (r profileListResult) renderText(w io.Writer) {
+	for _, profile := range r.Profiles {
+		marker := " "
+		if profile.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s: %d caught, %d seen, %s thrown\n", marker, profile.Name, profile.Caught, profile.Seen, plural(profile.Stats.BallsThrown, "ball"))
+	}
+}
+
+func (r profileListResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, profile := range r.Profiles {
+		rows = append(rows, []string{
+			profile.Name,
+			strconv.FormatBool(profile.Active),
+			strconv.Itoa(profile.Caught),
+			strconv.Itoa(profile.Seen),
+			strconv.Itoa(profile.Stats.BallsThrown),
+			strconv.Itoa(profile.Stats.AreasExplored),
+		})
+	}
+	return []string{"PROFILE", "ACTIVE", "CAUGHT", "SEEN", "BALLS THROWN", "AREAS EXPLORED"}, rows
+}
+
+// summarize describes a game state for profile listings
+func summarize(name string, active bool, state gameState) profileSummary {
+	return profileSummary{Name: name, Active: active, Caught: len(state.Pokemon), Seen: len(state.Pokedex), Stats: state.Stats}
+}
+
+func commandProfile(s *session, params ...string) (commandResult, error) {
+	if s.profileDir == "" {
+		return nil, fmt.Errorf("profiles are not available in this session")
+	}
+	if len(params) == 0 {
+		return profileListResult{Profiles: []profileSummary{summarize(s.profile, true, s.snapshot())}}, nil
+	}
+
+	switch params[0] {
+	case "list":
+		if len(params) != 1 {
+			return nil, fmt.Errorf("profile list does not take any parameters")
+		}
+		names, err := s.profileNames()
+		if err != nil {
+			return nil, err
+		}
+		result := profileListResult{Profiles: []profileSummary{}}
+		for _, name := range names {
+			if name == s.profile {
+				result.Profiles = append(result.Profiles, summarize(name, true, s.snapshot()))
+				continue
+			}
+			data, err := os.ReadFile(s.profilePath(name))
+			if err != nil {
+				return nil, err
+			}
+			state, err := decodeSave(data)
+			if err != nil {
+				return nil, fmt.Errorf("reading profile %s: %w", name, err)
+			}
+			result.Profiles = append(result.Profiles, summarize(name, false, state))
+		}
+		return result, nil
+
+	case "new", "switch":
+		if len(params) != 2 {
+			return nil, fmt.Errorf("usage: profile %s <name>", params[0])
+		}
+		name := params[1]
+		if err := checkProfileName(name); err != nil {
+			return nil, err
+		}
+		_, statErr := os.Stat(s.profilePath(name))
+		exists := statErr == nil || name == s.profile
+		if params[0] == "new" && exists {
+			return nil, fmt.Errorf("profile %s already exists", name)
+		}
+		if params[0] == "switch" && !exists {
+			return nil, fmt.Errorf("there is no profile named %s", name)
+		}
+		if name == s.profile {
+			return messageResult{Message: fmt.Sprintf("Already using profile %s", name)}, nil
+		}
+		if err := s.saveGame(); err != nil {
+			return nil, err
+		}
+		if err := s.useProfile(name); err != nil {
+			return nil, err
+		}
+		if params[0] == "new" {
+			if err := s.saveGame(); err != nil {
+				return nil, err
+			}
+			return messageResult{Message: fmt.Sprintf("Created profile %s", name)}, nil
+		}
+		return messageResult{Message: fmt.Sprintf("Switched to profile %s", name)}, nil
+
+	case "delete":
+		if len(params) != 2 {
+			return nil, fmt.Errorf("usage: profile delete <name>")
+		}
+		name := params[1]
+		if err := checkProfileName(name); err != nil {
+			return nil, err
+		}
+		if name == s.profile {
+			return nil, fmt.Errorf("can not delete the profile in use, switch to another one first")
+		}
+		err := os.Remove(s.profilePath(name))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("there is no profile named %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return messageResult{Message: fmt.Sprintf("Deleted profile %s", name)}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown profile command %q (expected list, new, switch or delete)", params[0])
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newProfileSession(t *testing.T, profileDir string) *session {
+	t.Helper()
+	s, _, _ := newTestSession(t)
+	s.profileDir = profileDir
+	if err := s.useProfile(defaultProfile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.sandbox = true
+	return s
+}
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	s := newProfileSession(t, dir)
+	lines := []string{
+		"catch magikarp --ball master",
+		"profile new misty",
+		"explore canalave-city-area",
+		"profile switch default",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := s.findCaught("magikarp"); err != nil || s.profile != defaultProfile {
+		t.Fatalf("expected to be back on the default profile with its magikarp")
+	}
+	if len(s.pokedex.entries) != 1 {
+		t.Errorf("expected the explore on misty's profile to stay there, got %d entries", len(s.pokedex.entries))
+	}
+
+	names, err := s.profileNames()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "default,misty" {
+		t.Errorf("unexpected profiles %v", names)
+	}
+
+	result, err := commandProfile(s, "list")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	profiles := result.(profileListResult).Profiles
+	if !profiles[0].Active || profiles[0].Caught != 1 || profiles[0].Stats.BallsThrown != 1 {
+		t.Errorf("unexpected default profile summary %+v", profiles[0])
+	}
+	if profiles[1].Active || profiles[1].Seen != 4 || profiles[1].Stats.AreasExplored != 1 {
+		t.Errorf("unexpected misty profile summary %+v", profiles[1])
+	}
+
+	if _, err := commandProfile(s, "delete", "default"); err == nil {
+		t.Errorf("expected deleting the active profile to fail")
+	}
+	if _, err := commandProfile(s, "new", "misty"); err == nil {
+		t.Errorf("expected creating an existing profile to fail")
+	}
+	if _, err := commandProfile(s, "switch", "brock"); err == nil {
+		t.Errorf("expected switching to a missing profile to fail")
+	}
+	if _, err := commandProfile(s, "new", "../escape"); err == nil {
+		t.Errorf("expected an invalid profile name to fail")
+	}
+	if _, err := commandProfile(s, "delete", "misty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "misty.json")); !os.IsNotExist(err) {
+		t.Errorf("expected misty's save file to be removed")
+	}
+}
+
+func TestProfileDeleteStaysInProfileDir(t *testing.T) {
+	dir := t.TempDir()
+	s := newProfileSession(t, filepath.Join(dir, "profiles"))
+	config := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(config, []byte("{}"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, action := range []string{"delete", "switch", "new"} {
+		if _, err := commandProfile(s, action, "../config"); err == nil || !strings.Contains(err.Error(), "invalid profile name") {
+			t.Errorf("%s: expected an invalid profile name error, got %v", action, err)
+		}
+	}
+	if _, err := os.Stat(config); err != nil {
+		t.Errorf("expected the config file to be left alone, got %v", err)
+	}
+}
+
+func TestProfileSwitchDropsRound(t *testing.T) {
+	s := newProfileSession(t, t.TempDir())
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	s.location = "canalave-city-area"
+	for _, line := range []string{"encounter", "quiz --hints"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s.wild == nil || s.quiz == nil {
+		t.Fatalf("expected a wild pokemon and a quiz round to start with")
+	}
+	if err := s.runLine("profile new misty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.wild != nil || s.quiz != nil {
+		t.Errorf("expected the wild pokemon and quiz round to stay with the default profile, got %+v and %+v", s.wild, s.quiz)
+	}
+	if _, err := commandQuiz(s, "skip"); err == nil {
+		t.Errorf("expected no quiz round on misty's profile")
+	}
+	if s.stats.Quiz.Rounds != 0 {
+		t.Errorf("expected misty's quiz score to be untouched, got %+v", s.stats.Quiz)
+	}
+}
+
+func TestMigrateLegacySave(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "save.json")
+	if err := os.WriteFile(legacy, []byte(`{"version": 1, "inventory": {"poke-ball": 7}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profileDir := filepath.Join(dir, "profiles")
+	if err := migrateLegacySave(legacy, profileDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := newProfileSession(t, profileDir)
+	if s.inventory["poke-ball"] != 7 {
+		t.Errorf("expected the old save to become the default profile, got %v", s.inventory)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("expected the old save file to be moved")
+	}
+}
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// quizGuesses is how many guesses a round of the quiz allows
+const quizGuesses = 3
+
+// silhouetteColor is what a quiz pokemon is drawn in, the blue of the
+// pokemon logo so it stands out on dark and light terminals alike
+var silhouetteColor = color.NRGBA{R: 0x3b, G: 0x4c, B: 0xca, A: 0xff}
+
+type pokeGeneration struct {
+	ID             int                 `json:"id"`
+	Name           string              `json:"name"`
+	PokemonSpecies []pokeNamedResource `json:"pokemon_species"`
+}
+
+func (c *pokeClient) getGeneration(name string) (pokeGeneration, error) {
+	var generation pokeGeneration
+	err := c.get(c.resourceUrl("generation", name), &generation)
+	return generation, err
+}
+
+// quizScore is the trainer's quiz record, kept with the rest of their stats
+type quizScore struct {
+	Rounds     int `json:"rounds"`
+	Correct    int `json:"correct"`
+	Streak     int `json:"streak"`
+	BestStreak int `json:"best_streak"`
+}
+
+func (q quizScore) String() string {
+	return fmt.Sprintf("%d/%d correct, streak %d (best %d)", q.Correct, q.Rounds, q.Streak, q.BestStreak)
+}
+
+// quizRound is the question the trainer is answering
+type quizRound struct {
+	answer  string
+	species string
+	// hints are revealed one at a time, shown counts how many are out
+	hints   []string
+	shown   int
+	guesses int
+	// deadline is when a timed round runs out, zero for untimed rounds
+	deadline time.Time
+}
+
+// nextHint reveals another hint, empty when there are none left
+func (r *quizRound) nextHint() string {
+	if r.shown >= len(r.hints) {
+		return ""
+	}
+	r.shown++
+	return r.hints[r.shown-1]
+}
+
+// normalizeGuess drops case and everything but letters and digits, so
+// "Mr. Mime" matches mr-mime
+func normalizeGuess(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// editDistance is the levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// guessMatches accepts a guess that is close enough to a name, a typo for
+// every three letters, unless it is at least as close to another of the
+// species, which keeps pichu from passing for pikachu
+func guessMatches(guess, name string, species []string) bool {
+	guess, name = normalizeGuess(guess), normalizeGuess(name)
+	distance := editDistance(guess, name)
+	if distance == 0 {
+		return true
+	}
+	if distance > max(1, len(name)/3) {
+		return false
+	}
+	for _, other := range species {
+		if other = normalizeGuess(other); other != name && editDistance(guess, other) <= distance {
+			return false
+		}
+	}
+	return true
+}
+
+// listSpecies fetches the names of every species the api knows about
+func (c *pokeClient) listSpecies() ([]string, error) {
+	var list pokeNamedList
+	if err := c.get(c.baseUrl+"/pokemon-species/?limit=100000", &list); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, result := range list.Results {
+		names = append(names, result.Name)
+	}
+	return names, nil
+}
+
+// silhouette fills in everything drawn in a sprite with one color
+func silhouette(img image.Image) image.Image {
+	bounds := img.Bounds()
+	filled := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if opaque(img.At(x, y)) {
+				filled.Set(x, y, silhouetteColor)
+			}
+		}
+	}
+	return filled
+}
+
+// redact hides a pokemon's name in its pokedex entry, which names it in
+// capitals more often than not
+func redact(text string, names ...string) string {
+	for _, name := range names {
+		for _, form := range []string{name, strings.ToUpper(name), strings.ToUpper(name[:1]) + name[1:]} {
+			text = strings.ReplaceAll(text, form, "???")
+		}
+	}
+	return text
+}
+
+// quizHints are the hints for a pokemon, from vague to giving it away
+func (s *session) quizHints(pokemon pokePokemon, species pokeSpecies) []string {
+	types := []string{}
+	for _, pokemonType := range pokemon.Types {
+		types = append(types, pokemonType.Type.Name)
+	}
+	total := 0
+	for _, stat := range pokemon.Stats {
+		total += stat.BaseStat
+	}
+	hints := []string{
+		fmt.Sprintf("It is %s type", strings.Join(types, "/")),
+		fmt.Sprintf("It is %.1f m tall, weighs %.1f kg and has a base stat total of %d", float64(pokemon.Height)/10, float64(pokemon.Weight)/10, total),
+	}
+	if text := species.flavorText(s.version); text != "" {
+		hints = append(hints, fmt.Sprintf("The pokedex says: %s", redact(text, species.Name, pokemon.Name)))
+	}
+	return append(hints, fmt.Sprintf("Its name starts with %s and has %s", strings.ToUpper(pokemon.Name[:1]), plural(len(normalizeGuess(pokemon.Name)), "letter")))
+}
+
+// quizCandidates lists the pokemon a round can ask about, or for a
+// generation the species
+func (s *session) quizCandidates(from, generation string) ([]string, error) {
+	if generation != "" {
+		gen, err := s.client.getGeneration(generation)
+		if err != nil {
+			return nil, err
+		}
+		return resourceNames(gen.PokemonSpecies), nil
+	}
+	if from == "" {
+		from = "area"
+		if len(s.caught) > 0 {
+			from = "caught"
+		}
+	}
+	switch from {
+	case "caught":
+		names := []string{}
+		seen := map[string]bool{}
+		for _, pokemon := range s.ordered() {
+			if !seen[pokemon.Name] {
+				seen[pokemon.Name] = true
+				names = append(names, pokemon.Name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("you have not caught any pokemon yet, use --from area or --gen")
+		}
+		return names, nil
+	case "area":
+		if s.location == "" {
+			return nil, fmt.Errorf("you are not in any area yet, explore or travel to one, or use --gen")
+		}
+		area, err := s.client.getLocationArea(s.location)
+		if err != nil {
+			return nil, err
+		}
+		names := areaPokemon(area, s.version)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no pokemon live in %s", area.Name)
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("quiz pokemon come --from caught or area, or a --gen")
+}
+
+type quizRoundResult struct {
+	// Silhouette is only drawn for text output
+	Silhouette string   `json:"-"`
+	Hints      []string `json:"hints"`
+	Guesses    int      `json:"guesses"`
+	Seconds    int      `json:"seconds,omitempty"`
+}
+
+func (r quizRoundResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, "Who's that pokemon?")
+	fmt.Fprint(w, r.Silhouette)
+	for _, hint := range r.Hints {
+		fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+	if r.Seconds > 0 {
+		fmt.Fprintf(w, "You have %d seconds and %s (guess <name>)\n", r.Seconds, plural(r.Guesses, "chance"))
+		return
+	}
+	fmt.Fprintf(w, "You have %s (guess <name>)\n", plural(r.Guesses, "chance"))
+}
+
+func (r quizRoundResult) tableRows() ([]string, [][]string) {
+	_, rows := fieldRows("guesses", strconv.Itoa(r.Guesses), "seconds", strconv.Itoa(r.Seconds))
+	for _, hint := range r.Hints {
+		rows = append(rows, []string{"hint", hint})
+	}
+	return []string{"FIELD", "VALUE"}, rows
+}
+
+type quizScoreResult struct {
+	Score quizScore `json:"score"`
+}
+
+func (r quizScoreResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Quiz: %s\n", r.Score)
+}
+
+func (r quizScoreResult) tableRows() ([]string, [][]string) {
+	return fieldRows(
+		"rounds", strconv.Itoa(r.Score.Rounds),
+		"correct", strconv.Itoa(r.Score.Correct),
+		"streak", strconv.Itoa(r.Score.Streak),
+		"best streak", strconv.Itoa(r.Score.BestStreak),
+	)
+}
+
+func commandQuiz(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"from", "gen", "time"}, []string{"hints"})
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 {
+		return s.quizAction(args)
+	}
+	if s.quiz != nil {
+		return nil, fmt.Errorf("a round is already going, guess the pokemon or use quiz skip")
+	}
+
+	generation := ""
+	if value, ok := flags["gen"]; ok {
+		if generation, err = parseGeneration(value); err != nil {
+			return nil, err
+		}
+	}
+	seconds := 0
+	if value, ok := flags["time"]; ok {
+		seconds, err = strconv.Atoi(value)
+		if err != nil || seconds < 1 {
+			return nil, fmt.Errorf("--time must be a number of seconds")
+		}
+	}
+	candidates, err := s.quizCandidates(flags["from"], generation)
+	if err != nil {
+		return nil, err
+	}
+
+	name := candidates[s.rng.Intn(len(candidates))]
+	if generation != "" {
+		// generations list species, asked about as the pokemon they are
+		// usually met as
+		species, err := s.client.getSpecies(name)
+		if err != nil {
+			return nil, err
+		}
+		name = species.defaultPokemon()
+	}
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return nil, err
+	}
+	species, err := s.client.getSpecies(pokemon.Species.Name)
+	if err != nil {
+		return nil, err
+	}
+	round := &quizRound{answer: pokemon.Name, species: species.Name, hints: s.quizHints(pokemon, species)}
+	if seconds > 0 {
+		round.deadline = s.now().Add(time.Duration(seconds) * time.Second)
+	}
+
+	result := quizRoundResult{Hints: []string{}, Guesses: quizGuesses, Seconds: seconds}
+	url := pokemon.spriteUrl(spriteOptions{version: s.version, versionGroup: s.versionGroup})
+	if flags["hints"] != "" || url == "" || s.output != formatText {
+		result.Hints = append(result.Hints, round.nextHint())
+	} else {
+		data, err := s.client.getRaw(url)
+		if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding sprite %s: %w", url, err)
+		}
+		result.Silhouette = renderSprite(silhouette(img), s.colors)
+	}
+	s.quiz = round
+	return result, nil
+}
+
+// quizAction handles quiz hint, quiz skip and quiz score
+func (s *session) quizAction(args []string) (commandResult, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("quiz command takes hint, skip or score")
+	}
+	switch args[0] {
+	case "score":
+		return quizScoreResult{Score: s.stats.Quiz}, nil
+	case "hint", "skip":
+		if s.quiz == nil {
+			return nil, fmt.Errorf("there is no quiz round going, start one with quiz")
+		}
+	default:
+		return nil, fmt.Errorf("quiz command takes hint, skip or score")
+	}
+
+	if args[0] == "skip" || s.quizTimedOut() {
+		return s.endQuiz("", false), nil
+	}
+	hint := s.quiz.nextHint()
+	if hint == "" {
+		return nil, fmt.Errorf("there are no hints left")
+	}
+	return messageResult{Message: fmt.Sprintf("Hint: %s", hint)}, nil
+}
+
+func (s *session) quizTimedOut() bool {
+	return !s.quiz.deadline.IsZero() && s.now().After(s.quiz.deadline)
+}
+
+type quizGuessResult struct {
+	Guess   string `json:"guess"`
+	Correct bool   `json:"correct"`
+	// Answer is only given away once the round is over
+	Answer      string    `json:"answer,omitempty"`
+	TimedOut    bool      `json:"timed_out,omitempty"`
+	GuessesLeft int       `json:"guesses_left"`
+	Hint        string    `json:"hint,omitempty"`
+	Score       quizScore `json:"score"`
+}
+
+func (r quizGuessResult) renderText(w io.Writer) {
+	switch {
+	case r.Correct:
+		fmt.Fprintf(w, "It's %s! Quiz: %s\n", r.Answer, r.Score)
+	case r.TimedOut:
+		fmt.Fprintf(w, "Time's up! It was %s. Quiz: %s\n", r.Answer, r.Score)
+	case r.Answer != "" && r.Guess == "":
+		fmt.Fprintf(w, "It was %s. Quiz: %s\n", r.Answer, r.Score)
+	case r.Answer != "":
+		fmt.Fprintf(w, "Wrong! It was %s. Quiz: %s\n", r.Answer, r.Score)
+	default:
+		fmt.Fprintf(w, "Not quite, %s left\n", plural(r.GuessesLeft, "chance"))
+		if r.Hint != "" {
+			fmt.Fprintf(w, "Hint: %s\n", r.Hint)
+		}
+	}
+}
+
+func (r quizGuessResult) tableRows() ([]string, [][]string) {
+	return fieldRows(
+		"guess", r.Guess,
+		"correct", strconv.FormatBool(r.Correct),
+		"answer", r.Answer,
+		"guesses left", strconv.Itoa(r.GuessesLeft),
+		"hint", r.Hint,
+		"score", r.Score.String(),
+	)
+}
+
+// endQuiz finishes the round and records it in the trainer's score
+func (s *session) endQuiz(guess string, correct bool) quizGuessResult {
+	round := s.quiz
+	s.quiz = nil
+	score := &s.stats.Quiz
+	score.Rounds++
+	if correct {
+		score.Correct++
+		score.Streak++
+		score.BestStreak = max(score.BestStreak, score.Streak)
+	} else {
+		score.Streak = 0
+	}
+	return quizGuessResult{Guess: guess, Correct: correct, Answer: round.answer, Score: *score}
+}
+
+func commandGuess(s *session, params ...string) (commandResult, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("guess command requires a pokemon name")
+	}
+	if s.quiz == nil {
+		return nil, fmt.Errorf("there is no quiz round going, start one with quiz")
+	}
+	guess := strings.Join(params, " ")
+	if s.quizTimedOut() {
+		result := s.endQuiz(guess, false)
+		result.TimedOut = true
+		return result, nil
+	}
+	species, err := s.client.listSpecies()
+	if err != nil {
+		return nil, err
+	}
+	if guessMatches(guess, s.quiz.answer, species) || guessMatches(guess, s.quiz.species, species) {
+		return s.endQuiz(guess, true), nil
+	}
+	s.quiz.guesses++
+	if s.quiz.guesses >= quizGuesses {
+		return s.endQuiz(guess, false), nil
+	}
+	return quizGuessResult{
+		Guess:       guess,
+		GuessesLeft: quizGuesses - s.quiz.guesses,
+		Hint:        s.quiz.nextHint(),
+		Score:       s.stats.Quiz,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuessMatches(t *testing.T) {
+	cases := []struct {
+		guess, name string
+		expected    bool
+	}{
+		{"pikachu", "pikachu", true},
+		{"Pikachoo", "pikachu", true},
+		{"mr. mime", "mr-mime", true},
+		{"raichu", "pikachu", false},
+		{"mewtwo", "mew", false},
+		{"mow", "mew", true},
+		{"pichu", "pikachu", false},
+		{"porygon2", "porygon", false},
+		{"porygon-2", "porygon", false},
+		{"porygonn", "porygon2", false},
+		{"porygom", "porygon", true},
+	}
+	species := []string{"mew", "mewtwo", "pichu", "pikachu", "raichu", "porygon", "porygon2", "mr-mime"}
+	for _, c := range cases {
+		if matches := guessMatches(c.guess, c.name, species); matches != c.expected {
+			t.Errorf("%q for %q: expected %v, got %v", c.guess, c.name, c.expected, matches)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	text := redact("It recharges a fellow PIKACHU. Pikachu and pikachu.", "pikachu")
+	if text != "It recharges a fellow ???. ??? and ???." {
+		t.Errorf("expected the name to be hidden, got %q", text)
+	}
+}
+
+func TestQuizRound(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	for _, line := range []string{"quiz --hints", "guess raichu", "quiz hint", "guess pikachoo", "quiz score"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := []string{
+		"Who's that pokemon?\nHint: It is electric type\nYou have 3 chances (guess <name>)",
+		"Not quite, 2 chances left\nHint: It is 0.4 m tall, weighs 6.0 kg and has a base stat total of 320",
+		"Hint: The pokedex says: It occasionally uses an electric shock to recharge a fellow ??? that is in a weakened state.",
+		"It's pikachu! Quiz: 1/1 correct, streak 1 (best 1)",
+		"Quiz: 1/1 correct, streak 1 (best 1)",
+	}
+	for _, text := range expected {
+		if !strings.Contains(out.String(), text) {
+			t.Errorf("expected %q, got %q", text, out.String())
+		}
+	}
+	if s.quiz != nil {
+		t.Errorf("expected the round to be over")
+	}
+	if state := s.snapshot(); state.Stats.Quiz.Correct != 1 {
+		t.Errorf("expected the score to be saved with the profile, got %+v", state.Stats.Quiz)
+	}
+}
+
+func TestQuizSilhouette(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	var data bytes.Buffer
+	if err := png.Encode(&data, testSprite()); err != nil {
+		t.Fatalf("encoding sprite: %v", err)
+	}
+	s.client.cache.Add("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png", data.Bytes())
+	s.colors = colorTrue
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	if err := s.runLine("quiz"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Who's that pokemon?\n\x1b[38;2;59;76;202m\x1b[48;2;59;76;202m▀\x1b[0m\x1b[38;2;59;76;202m▄\x1b[0m\nYou have 3 chances"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("expected a silhouette, got %q", out.String())
+	}
+}
+
+func TestQuizEndings(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	s.stats.Quiz = quizScore{Rounds: 4, Correct: 4, Streak: 4, BestStreak: 4}
+
+	lines := []string{"quiz --hints", "quiz --hints", "guess magikarp", "guess magikarp", "guess magikarp", "quiz --hints --time 10"}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	start := s.now()
+	s.now = func() time.Time { return start.Add(11 * time.Second) }
+	for _, line := range []string{"guess pikachu", "quiz --hints", "quiz skip", "guess pikachu"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []string{
+		"Wrong! It was pikachu. Quiz: 4/5 correct, streak 0 (best 4)",
+		"You have 10 seconds and 3 chances",
+		"Time's up! It was pikachu. Quiz: 4/6 correct, streak 0 (best 4)",
+		"It was pikachu. Quiz: 4/7 correct, streak 0 (best 4)",
+	}
+	for _, text := range expected {
+		if !strings.Contains(out.String(), text) {
+			t.Errorf("expected %q, got %q", text, out.String())
+		}
+	}
+	for _, text := range []string{"a round is already going", "there is no quiz round going"} {
+		if !strings.Contains(errOut.String(), text) {
+			t.Errorf("expected %q, got %q", text, errOut.String())
+		}
+	}
+}
+
+func TestQuizCandidates(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	if _, err := s.quizCandidates("", ""); err == nil {
+		t.Errorf("expected an error without caught pokemon or an area")
+	}
+
+	names, err := s.quizCandidates("", "generation-i")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(names, []string{"pikachu", "raichu", "tentacool", "magikarp", "gyarados"}) {
+		t.Errorf("expected the species of generation i, got %v", names)
+	}
+
+	s.location = "canalave-city-area"
+	names, err = s.quizCandidates("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(names, "wingull") {
+		t.Errorf("expected the pokemon of the area, got %v", names)
+	}
+
+	if err := s.runLine("quiz --gen 1 --hints"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.quiz == nil || !slices.Contains([]string{"pikachu", "raichu", "tentacool", "magikarp", "gyarados"}, s.quiz.answer) {
+		t.Errorf("expected a round about a generation i pokemon, got %+v", s.quiz)
+	}
+}
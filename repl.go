@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// errExit is returned by the exit command to stop the repl loop, so the
+// repl can be embedded without the process being torn down underneath it
+var errExit = errors.New("exit requested")
+
+// errInterrupted is returned by run when the session is interrupted (ctrl-c)
+// while waiting for input
+var errInterrupted = errors.New("interrupted")
+
+type config struct {
+	nextUrl *string
+	prevUrl *string
+}
+
+// session holds everything a single pokedex needs: the command table, the
+// api client, map pagination, the pokedex, caught pokemon and inventory, user aliases and where output
+// goes.
+// sessions share nothing, so several can run side by side in one process
+type session struct {
+	out       io.Writer
+	errOut    io.Writer
+	output    outputFormat
+	commands  map[string]cliCommand
+	client    *pokeClient
+	indexUrls config
+	// caught holds every pokemon the trainer owns by id, party and boxes
+	// hold the ids in the order the trainer put them away
+	caught    map[int]caughtPokemon
+	party     []int
+	boxes     [][]int
+	nextID    int
+	pokedex   *pokedex
+	inventory map[string]int
+	stats     trainerStats
+	location  string
+	version   string
+	// versionGroup is the group of the selected version, which is what
+	// move learnsets and sprites are keyed by
+	versionGroup string
+	wild         *wildPokemon
+	quiz         *quizRound
+	sandbox      bool
+	// shinyOdds is the one in n shiny chance the trainer set, 0 to follow
+	// the game version
+	shinyOdds  int
+	aliases    map[string]string
+	configPath string
+	player     string
+	savePath   string
+	profile    string
+	profileDir string
+	// colors is what the terminal can show when drawing sprites
+	colors colorMode
+	// interrupts stops the read loop between commands, so the game is
+	// saved on the goroutine that changes it
+	interrupts <-chan os.Signal
+	// quit ends the process when a second interrupt comes in while a
+	// command is still running
+	quit       func(code int)
+	rng        *rand.Rand
+	shakeDelay time.Duration
+	now        func() time.Time
+}
+
+func newSession(client *pokeClient, out, errOut io.Writer) *session {
+	return &session{
+		out:        out,
+		errOut:     errOut,
+		output:     formatText,
+		commands:   defaultCommands(),
+		client:     client,
+		caught:     map[int]caughtPokemon{},
+		party:      []int{},
+		boxes:      emptyBoxes(),
+		pokedex:    newPokedex(),
+		inventory:  startingInventory(),
+		profile:    defaultProfile,
+		aliases:    map[string]string{},
+		colors:     detectColors(os.Getenv),
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		shakeDelay: 700 * time.Millisecond,
+		quit:       os.Exit,
+		now:        time.Now,
+	}
+}
+
+func cleanInput(text string) []string {
+	text = strings.ToLower(text)
+	splitStrings := strings.Fields(text)
+	return splitStrings
+}
+
+// splitInput splits a command into words like cleanInput, but only
+// lowercases the command word. the arguments are lowercased when the
+// command runs, unless it keeps their case (see cliCommand.keepCase)
+func splitInput(text string) []string {
+	words := strings.Fields(text)
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0])
+	}
+	return words
+}
+
+func lowerWords(words []string) []string {
+	lowered := make([]string, len(words))
+	for i, word := range words {
+		lowered[i] = strings.ToLower(word)
+	}
+	return lowered
+}
+
+// status is for progress messages that are not part of a command's result.
+// they are only shown for text output so json and yaml stay machine readable
+func (s *session) status(format string, a ...any) {
+	if s.output == formatText {
+		fmt.Fprintf(s.out, format, a...)
+	}
+}
+
+// errUnknownCommand is reported when the first word of a command matches
+// neither a command, a built-in alias nor a user alias
+var errUnknownCommand = errors.New("Unknown command")
+
+// pipeable is implemented by results that can feed another command, their
+// values are appended to the next command's arguments
+type pipeable interface {
+	pipeValues() []string
+}
+
+// parseLine splits a line of input into statements separated by ';', each
+// a pipeline of commands separated by '|'. an alias definition takes the
+// rest of the line, since its body is made of statements itself
+func parseLine(line string) ([][][]string, error) {
+	pipelines := [][][]string{}
+	rest := line
+	for strings.TrimSpace(rest) != "" {
+		if words := cleanInput(rest); words[0] == "alias" {
+			pipelines = append(pipelines, [][]string{words})
+			break
+		}
+		statement, remaining, _ := strings.Cut(rest, ";")
+		rest = remaining
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		stages := [][]string{}
+		for _, stage := range strings.Split(statement, "|") {
+			words := splitInput(stage)
+			if len(words) == 0 {
+				return nil, fmt.Errorf("missing command in pipe")
+			}
+			stages = append(stages, words)
+		}
+		pipelines = append(pipelines, stages)
+	}
+	return pipelines, nil
+}
+
+// runLine executes a line of input, rendering the result of each statement.
+// command errors are reported to errOut, only errExit is returned
+func (s *session) runLine(line string) error {
+	pipelines, err := parseLine(line)
+	if err != nil {
+		s.report(nil, err)
+		return nil
+	}
+	for _, stages := range pipelines {
+		if err := s.runPipeline(stages, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPipeline runs a statement and renders its final result
+func (s *session) runPipeline(stages [][]string, depth int) error {
+	result, err := s.pipe(stages, depth)
+	if errors.Is(err, errExit) {
+		return err
+	}
+	s.report(result, err)
+	return nil
+}
+
+// report renders a result, or the error that stopped a command
+func (s *session) report(result commandResult, err error) {
+	if errors.Is(err, errUnknownCommand) {
+		fmt.Fprintf(s.errOut, "Unknown command\n")
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(s.errOut, "Error executing command: ", err)
+	} else if result != nil {
+		if err := renderResult(s.out, s.output, result); err != nil {
+			fmt.Fprintln(s.errOut, "Error rendering output: ", err)
+		}
+	}
+	fmt.Fprintln(s.out)
+}
+
+// pipe runs each stage of a pipeline with the previous stage's values added
+// to its arguments, and returns the result of the last stage
+func (s *session) pipe(stages [][]string, depth int) (commandResult, error) {
+	var result commandResult
+	for i, stage := range stages {
+		input := stage
+		if i > 0 {
+			values, ok := result.(pipeable)
+			if !ok {
+				return nil, fmt.Errorf("the output of %s cannot be piped", stages[i-1][0])
+			}
+			input = append(append([]string{}, stage...), values.pipeValues()...)
+		}
+		var err error
+		result, err = s.execute(input, depth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// execute runs one command and returns its result without rendering it.
+// user aliases are expanded here, every statement of a macro but the last
+// is rendered as it runs and the last one's result is returned. depth
+// counts how many aliases deep the expansion is
+func (s *session) execute(input []string, depth int) (commandResult, error) {
+	command := input[0]
+	if body, isAlias := s.aliases[command]; isAlias {
+		if depth >= maxAliasDepth {
+			return nil, fmt.Errorf("alias %s expands too deeply", command)
+		}
+		pipelines, err := expandMacro(command, body, input[1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(pipelines) == 0 {
+			return nil, nil
+		}
+		for _, stages := range pipelines[:len(pipelines)-1] {
+			if err := s.runPipeline(stages, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		return s.pipe(pipelines[len(pipelines)-1], depth+1)
+	}
+
+	cmdData, exists := s.lookupCommand(command)
+	if !exists {
+		return nil, errUnknownCommand
+	}
+	params := input[1:]
+	if !cmdData.keepCase {
+		params = lowerWords(params)
+	}
+	return cmdData.callback(s, params...)
+}
+
+// run reads commands from in until exit is called or the input runs out,
+// then saves the game if the session has a save file
+func (s *session) run(in io.Reader) error {
+	err := s.readLoop(in)
+	if s.savePath != "" {
+		if saveErr := s.saveGame(); saveErr != nil {
+			fmt.Fprintln(s.errOut, "Error saving game: ", saveErr)
+		}
+	}
+	return err
+}
+
+// inputLine is a line read from the input, or the error that stopped it
+type inputLine struct {
+	text string
+	err  error
+}
+
+// readLines scans the input on its own goroutine, so the read loop can wait
+// for an interrupt at the same time. the channel is closed when the input
+// runs out
+func readLines(in io.Reader) <-chan inputLine {
+	lines := make(chan inputLine)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			lines <- inputLine{text: scanner.Text()}
+		}
+		if err := scanner.Err(); err != nil {
+			lines <- inputLine{err: err}
+		}
+	}()
+	return lines
+}
+
+func (s *session) readLoop(in io.Reader) error {
+	lines := readLines(in)
+	for {
+		fmt.Fprint(s.out, "Pokedex >")
+		var line inputLine
+		var more bool
+		select {
+		case line, more = <-lines:
+		case <-s.interrupts:
+			fmt.Fprintln(s.out)
+			return errInterrupted
+		}
+		if !more || line.err != nil {
+			return line.err
+		}
+		interrupted, err := s.runWatched(line.text)
+		if err != nil {
+			if errors.Is(err, errExit) {
+				return nil
+			}
+			return err
+		}
+		if interrupted {
+			return errInterrupted
+		}
+	}
+}
+
+// runWatched runs a line of input while watching for interrupts. the first
+// one stops the repl once the command is done, so the game is saved in a
+// consistent state, and a second one quits right away without saving
+func (s *session) runWatched(line string) (bool, error) {
+	if s.interrupts == nil {
+		return false, s.runLine(line)
+	}
+	done := make(chan struct{})
+	interrupted := make(chan bool)
+	go func() {
+		stopping := false
+		for {
+			select {
+			case <-done:
+				interrupted <- stopping
+				return
+			case <-s.interrupts:
+				if stopping {
+					s.quit(130)
+					continue
+				}
+				stopping = true
+				fmt.Fprintln(s.errOut, "\nStopping once this command is done, press ctrl-c again to quit without saving")
+			}
+		}
+	}()
+	err := s.runLine(line)
+	close(done)
+	return <-interrupted, err
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type settingsResult struct {
+	Output  outputFormat `json:"output"`
+	Sandbox bool         `json:"sandbox"`
+	Version string       `json:"version"`
+	Lead    string       `json:"lead"`
+	// ShinyOdds is the one in n shiny chance, 0 when it follows the version
+	ShinyOdds int    `json:"shiny_odds"`
+	Player    string `json:"player"`
+}
+
+func (r settingsResult) pairs() []string {
+	return []string{
+		"output", string(r.Output),
+		"sandbox", onOff(r.Sandbox),
+		"version", versionLabel(r.Version),
+		"lead", r.Lead,
+		"shiny-odds", shinyOddsLabel(r.ShinyOdds),
+		"player", playerLabel(r.Player),
+	}
+}
+
+func (r settingsResult) renderText(w io.Writer) {
+	pairs := r.pairs()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fmt.Fprintf(w, "%s: %s\n", pairs[i], pairs[i+1])
+	}
+}
+
+func (r settingsResult) tableRows() ([]string, [][]string) {
+	_, rows := fieldRows(r.pairs()...)
+	return []string{"SETTING", "VALUE"}, rows
+}
+
+// versionLabel names the selected game version, "all" when there is none
+func versionLabel(version string) string {
+	if version == "" {
+		return "all"
+	}
+	return version
+}
+
+// playerLabel names the command cries are played with
+func playerLabel(player string) string {
+	if player == "" {
+		return "none"
+	}
+	return player
+}
+
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
+
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("expected on or off, got %q", value)
+	}
+	return enabled, nil
+}
+
+func (s *session) settings() settingsResult {
+	return settingsResult{
+		Output:    s.output,
+		Sandbox:   s.sandbox,
+		Version:   s.version,
+		Lead:      s.leadName(),
+		ShinyOdds: s.shinyOdds,
+		Player:    s.player,
+	}
+}
+
+func commandSet(s *session, params ...string) (commandResult, error) {
+	if len(params) == 0 {
+		return s.settings(), nil
+	}
+	// the player is a command line, kept as typed since it may name a file
+	if strings.ToLower(params[0]) == "player" && len(params) > 1 {
+		return s.setPlayer(strings.Join(params[1:], " "))
+	}
+	params = lowerWords(params)
+	if len(params) != 2 {
+		return nil, fmt.Errorf("set command takes a setting name and a value")
+	}
+	switch params[0] {
+	case "output":
+		format, err := parseOutputFormat(params[1])
+		if err != nil {
+			return nil, err
+		}
+		s.output = format
+		return messageResult{Message: fmt.Sprintf("output set to %s", format)}, nil
+	case "sandbox":
+		enabled, err := parseOnOff(params[1])
+		if err != nil {
+			return nil, err
+		}
+		s.sandbox = enabled
+		return messageResult{Message: fmt.Sprintf("sandbox set to %s", onOff(enabled))}, nil
+	case "version":
+		return commandVersion(s, params[1])
+	case "lead":
+		if err := s.setLead(params[1]); err != nil {
+			return nil, err
+		}
+		return messageResult{Message: fmt.Sprintf("lead set to %s", s.leadName())}, nil
+	case "shiny-odds":
+		odds, err := parseShinyOdds(params[1])
+		if err != nil {
+			return nil, err
+		}
+		s.shinyOdds = odds
+		return messageResult{Message: fmt.Sprintf("shiny odds set to %s", shinyOddsLabel(odds))}, nil
+	default:
+		return nil, fmt.Errorf("unknown setting %q", params[0])
+	}
+}
+
+// setPlayer changes the command cries are played with, none removing it.
+// it is kept in the config file rather than the save, like the aliases
+func (s *session) setPlayer(player string) (commandResult, error) {
+	if strings.ToLower(player) == "none" {
+		player = ""
+	}
+	s.player = player
+	if err := s.saveConfig(); err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("player set to %s", playerLabel(player))}, nil
+}
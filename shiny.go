@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// modernShinyOdds is the one in n shiny chance since generation vi
+	modernShinyOdds = 4096
+	// classicShinyOdds is the shiny chance of generations ii to v
+	classicShinyOdds = 8192
+)
+
+type pokeVersionGroup struct {
+	ID         int               `json:"id"`
+	Name       string            `json:"name"`
+	Generation pokeNamedResource `json:"generation"`
+}
+
+func (c *pokeClient) getVersionGroup(name string) (pokeVersionGroup, error) {
+	var group pokeVersionGroup
+	err := c.get(c.resourceUrl("version-group", name), &group)
+	return group, err
+}
+
+// generationShinyOdds is the one in n shiny chance in a generation's games,
+// 0 for generation i which had no shiny pokemon
+func generationShinyOdds(generation string) int {
+	switch generation {
+	case "generation-i":
+		return 0
+	case "generation-ii", "generation-iii", "generation-iv", "generation-v":
+		return classicShinyOdds
+	}
+	return modernShinyOdds
+}
+
+// shinyOddsIn is the one in n chance of a pokemon met in a game version
+// being shiny: the odds the trainer set, otherwise those of the version's
+// generation, or of the newest games when there is no version
+func (s *session) shinyOddsIn(version string) (int, error) {
+	if s.shinyOdds > 0 {
+		return s.shinyOdds, nil
+	}
+	if version == "" {
+		return modernShinyOdds, nil
+	}
+	gameVersion, err := s.client.getVersion(version)
+	if err != nil {
+		return 0, err
+	}
+	group, err := s.client.getVersionGroup(gameVersion.VersionGroup.Name)
+	if err != nil {
+		return 0, err
+	}
+	return generationShinyOdds(group.Generation.Name), nil
+}
+
+// rollShiny decides whether a pokemon met in a game version is shiny
+func (s *session) rollShiny(version string) (bool, error) {
+	odds, err := s.shinyOddsIn(version)
+	if err != nil || odds == 0 {
+		return false, err
+	}
+	return s.rng.Intn(odds) == 0, nil
+}
+
+// shinyOddsLabel describes the shiny odds setting, 0 leaving them to the
+// game version
+func shinyOddsLabel(odds int) string {
+	if odds == 0 {
+		return "by version"
+	}
+	return fmt.Sprintf("1 in %d", odds)
+}
+
+// parseShinyOdds reads the shiny odds setting, a one in n chance or
+// "version" to follow the game version
+func parseShinyOdds(value string) (int, error) {
+	if value == "version" {
+		return 0, nil
+	}
+	odds, err := strconv.Atoi(value)
+	if err != nil || odds < 1 {
+		return 0, fmt.Errorf("shiny odds must be version, or n for a one in n chance")
+	}
+	return odds, nil
+}
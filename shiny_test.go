@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShinyOddsFollowVersion(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	cases := map[string]int{
+		"":         modernShinyOdds,
+		"red":      0,
+		"diamond":  classicShinyOdds,
+		"platinum": classicShinyOdds,
+	}
+	for version, expected := range cases {
+		odds, err := s.shinyOddsIn(version)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if odds != expected {
+			t.Errorf("%q: expected 1 in %d, got 1 in %d", version, expected, odds)
+		}
+	}
+
+	s.shinyOdds = 100
+	if odds, _ := s.shinyOddsIn("red"); odds != 100 {
+		t.Errorf("expected the odds set by the trainer, got 1 in %d", odds)
+	}
+}
+
+func TestNoShiniesInGenerationOne(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	for i := 0; i < 100; i++ {
+		shiny, err := s.rollShiny("red")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if shiny {
+			t.Fatalf("expected no shiny pokemon in red")
+		}
+	}
+}
+
+func TestShinyEncounterAndCatch(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	lines := []string{
+		"set shiny-odds 1",
+		"travel canalave-city-area",
+		"encounter --method surf --version platinum",
+		"catch --ball master",
+		"party",
+		"pokedex caught",
+		"inspect 1",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := []string{
+		"shiny odds set to 1 in 1",
+		"A wild shiny tentacool (level ",
+		"tentacool was caught! It's shiny!",
+		"#1 tentacool (level ",
+		", shiny)",
+		" - tentacool (caught, shiny, 1 attempt)",
+		"Shiny: yes",
+	}
+	for _, text := range expected {
+		if !strings.Contains(out.String(), text) {
+			t.Errorf("expected %q, got %q", text, out.String())
+		}
+	}
+	if !s.caught[1].Shiny {
+		t.Errorf("expected the caught tentacool to be shiny")
+	}
+}
+
+func TestShinySprite(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	s.shinyOdds = 1
+	for _, line := range []string{"catch pikachu --ball master", "inspect 1"} {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !strings.Contains(out.String(), "Sprite: https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/shiny/25.png") {
+		t.Errorf("expected the shiny sprite, got %q", out.String())
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// flavorEntry is a species' pokedex entry in one game version
+type flavorEntry struct {
+	Version string `json:"version"`
+	Text    string `json:"text"`
+}
+
+// speciesDetails is what the species endpoint says about a pokemon, shown
+// by the species command and when inspecting a caught pokemon
+type speciesDetails struct {
+	Name          string        `json:"name"`
+	Genus         string        `json:"genus"`
+	Generation    string        `json:"generation"`
+	Legendary     bool          `json:"legendary"`
+	Mythical      bool          `json:"mythical"`
+	Habitat       string        `json:"habitat"`
+	Color         string        `json:"color"`
+	Shape         string        `json:"shape"`
+	EggGroups     []string      `json:"egg_groups"`
+	GenderRate    int           `json:"gender_rate"`
+	CaptureRate   int           `json:"capture_rate"`
+	BaseHappiness int           `json:"base_happiness"`
+	GrowthRate    string        `json:"growth_rate"`
+	FlavorText    []flavorEntry `json:"flavor_text"`
+}
+
+// newSpeciesDetails collects a species' details in english, with the
+// pokedex entries of one game version when version is set
+func newSpeciesDetails(species pokeSpecies, version string) speciesDetails {
+	details := speciesDetails{
+		Name:          species.Name,
+		Generation:    species.Generation.Name,
+		Legendary:     species.IsLegendary,
+		Mythical:      species.IsMythical,
+		Color:         species.Color.Name,
+		EggGroups:     resourceNames(species.EggGroups),
+		GenderRate:    species.GenderRate,
+		CaptureRate:   species.CaptureRate,
+		BaseHappiness: species.BaseHappiness,
+		GrowthRate:    species.GrowthRate.Name,
+		FlavorText:    []flavorEntry{},
+	}
+	for _, genus := range species.Genera {
+		if genus.Language.Name == "en" {
+			details.Genus = genus.Genus
+		}
+	}
+	// newer species have no habitat, and a few no shape
+	if species.Habitat != nil {
+		details.Habitat = species.Habitat.Name
+	}
+	if species.Shape != nil {
+		details.Shape = species.Shape.Name
+	}
+	for _, entry := range species.FlavorTextEntries {
+		if entry.Language.Name != "en" || (version != "" && entry.Version.Name != version) {
+			continue
+		}
+		details.FlavorText = append(details.FlavorText, flavorEntry{
+			Version: entry.Version.Name,
+			Text:    strings.Join(strings.Fields(entry.FlavorText), " "),
+		})
+	}
+	return details
+}
+
+// genderRatio describes a gender rate, the chance of a female in eighths
+func genderRatio(genderRate int) string {
+	if genderRate < 0 {
+		return "genderless"
+	}
+	female := float64(genderRate) * 100 / 8
+	return fmt.Sprintf("%g%% male, %g%% female", 100-female, female)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+func (d speciesDetails) pairs() []string {
+	return []string{
+		"genus", orUnknown(d.Genus),
+		"generation", orUnknown(d.Generation),
+		"legendary", yesNo(d.Legendary),
+		"mythical", yesNo(d.Mythical),
+		"habitat", orUnknown(d.Habitat),
+		"color", orUnknown(d.Color),
+		"shape", orUnknown(d.Shape),
+		"egg groups", joinOrNone(d.EggGroups),
+		"gender ratio", genderRatio(d.GenderRate),
+		"capture rate", strconv.Itoa(d.CaptureRate),
+		"base happiness", strconv.Itoa(d.BaseHappiness),
+		"growth rate", orUnknown(d.GrowthRate),
+	}
+}
+
+// renderText writes the details as "Key: value" lines, followed by the
+// pokedex entries
+func (d speciesDetails) renderText(w io.Writer) {
+	pairs := d.pairs()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fmt.Fprintf(w, "%s: %s\n", strings.ToUpper(pairs[i][:1])+pairs[i][1:], pairs[i+1])
+	}
+	if len(d.FlavorText) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Pokedex entries:")
+	for _, entry := range d.FlavorText {
+		fmt.Fprintf(w, "\t-%s: %s\n", entry.Version, entry.Text)
+	}
+}
+
+// rows are the details as table rows, one per pokedex entry at the end
+func (d speciesDetails) rows() []string {
+	pairs := d.pairs()
+	for _, entry := range d.FlavorText {
+		pairs = append(pairs, "pokedex ("+entry.Version+")", entry.Text)
+	}
+	return pairs
+}
+
+type speciesResult struct {
+	speciesDetails
+	Varieties []string `json:"varieties"`
+}
+
+func (r speciesResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Species: %s\n", r.Name)
+	fmt.Fprintf(w, "Varieties: %s\n", joinOrNone(r.Varieties))
+	r.speciesDetails.renderText(w)
+}
+
+func (r speciesResult) pipeValues() []string {
+	return r.Varieties
+}
+
+func (r speciesResult) tableRows() ([]string, [][]string) {
+	pairs := append([]string{"species", r.Name, "varieties", joinOrNone(r.Varieties)}, r.rows()...)
+	return fieldRows(pairs...)
+}
+
+func commandSpecies(s *session, params ...string) (commandResult, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("species command requires a species or pokemon name, or the id or nickname of a caught pokemon")
+	}
+	name := params[0]
+	if caught, err := s.findCaught(name); err == nil {
+		name = caught.Name
+	}
+	// species and pokemon mostly share names, forms such as deoxys-attack
+	// are looked up through their species
+	species, err := s.client.getSpecies(name)
+	if err != nil {
+		pokemon, pokemonErr := s.client.getPokemon(name)
+		if pokemonErr != nil {
+			return nil, err
+		}
+		if species, err = s.client.getSpecies(pokemon.Species.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	result := speciesResult{speciesDetails: newSpeciesDetails(species, s.version), Varieties: []string{}}
+	for _, variety := range species.Varieties {
+		result.Varieties = append(result.Varieties, variety.Pokemon.Name)
+	}
+	return result, nil
+}
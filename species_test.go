@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenderRatio(t *testing.T) {
+	cases := map[int]string{
+		-1: "genderless",
+		0:  "100% male, 0% female",
+		1:  "87.5% male, 12.5% female",
+		4:  "50% male, 50% female",
+		8:  "0% male, 100% female",
+	}
+	for rate, expected := range cases {
+		if actual := genderRatio(rate); actual != expected {
+			t.Errorf("%d: expected %q, got %q", rate, expected, actual)
+		}
+	}
+}
+
+func TestSpeciesCommand(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-magikarp.json")
+	if err := s.runLine("nickname 1 splashy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := []string{
+		"species pikachu",
+		"species splashy",
+		"species missingno",
+		"species",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for _, expected := range []string{
+		"Species: pikachu\nVarieties: pikachu\nGenus: Mouse Pokémon\n",
+		"Habitat: forest\nColor: yellow\nShape: quadruped\nEgg groups: ground, fairy\nGender ratio: 50% male, 50% female\nCapture rate: 190\n",
+		"\t-red: When several of these POKéMON gather, their electricity could build and cause lightning storms.\n",
+		"Species: magikarp\n",
+		"Habitat: waters-edge\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got %q", expected, out.String())
+		}
+	}
+	// the japanese entry is left out
+	if strings.Count(out.String(), "-diamond:") != 2 {
+		t.Errorf("expected one english diamond entry per species, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "species command requires") {
+		t.Errorf("expected a missing name error, got %q", errOut.String())
+	}
+}
+
+func TestSpeciesFlavorTextByVersion(t *testing.T) {
+	s, out, _ := newTestSession(t)
+	if err := s.runLine("version platinum"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.runLine("species pikachu"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Pokedex entries:\n\t-platinum: It occasionally uses an electric shock") {
+		t.Errorf("expected the platinum entry, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "-red:") || strings.Contains(out.String(), "-diamond:") {
+		t.Errorf("expected only the platinum entry, got %q", out.String())
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// spriteOptions picks one of a pokemon's sprites
+type spriteOptions struct {
+	version      string
+	versionGroup string
+	// generation limits the lookup to one generation's sprites, e.g.
+	// generation-iii
+	generation string
+	shiny      bool
+	back       bool
+}
+
+// field is the name the api gives the sprite, e.g. back_shiny
+func (o spriteOptions) field() string {
+	side := "front"
+	if o.back {
+		side = "back"
+	}
+	if o.shiny {
+		return side + "_shiny"
+	}
+	return side + "_default"
+}
+
+// defaultSprite is the pokemon's current sprite for a field
+func (p pokePokemon) defaultSprite(field string) string {
+	switch field {
+	case "front_shiny":
+		return p.Sprites.FrontShiny
+	case "back_default":
+		return p.Sprites.BackDefault
+	case "back_shiny":
+		return p.Sprites.BackShiny
+	}
+	return p.Sprites.FrontDefault
+}
+
+// spriteUrl picks the sprite a pokemon had in a game version, falling back
+// to its current sprite when the version has none or none is set. with a
+// generation set it only looks at that generation's sprites, and returns
+// nothing when there are none.
+// the api keys version sprites by generation and then by version or version
+// group, with the dashes dropped in a few names (omegaruby-alphasapphire),
+// so the lookup walks the json rather than the typed fields
+func (p pokePokemon) spriteUrl(opts spriteOptions) string {
+	field := opts.field()
+	fallback := p.defaultSprite(field)
+	if opts.version == "" && opts.generation == "" {
+		return fallback
+	}
+	data, err := json.Marshal(p.Sprites.Versions)
+	if err != nil {
+		return fallback
+	}
+	var generations map[string]map[string]map[string]any
+	if err := json.Unmarshal(data, &generations); err != nil {
+		return fallback
+	}
+
+	undashed := strings.ReplaceAll(opts.versionGroup, "-", "")
+	first := ""
+	for _, generation := range sortedKeys(generations) {
+		if opts.generation != "" && generation != opts.generation {
+			continue
+		}
+		sets := generations[generation]
+		for _, key := range sortedKeys(sets) {
+			url, _ := sets[key][field].(string)
+			if url == "" {
+				continue
+			}
+			if opts.version != "" && (key == opts.version || key == opts.versionGroup || strings.ReplaceAll(key, "-", "") == undashed) {
+				return url
+			}
+			if first == "" {
+				first = url
+			}
+		}
+	}
+	if opts.generation != "" {
+		return first
+	}
+	return fallback
+}
+
+var romanNumerals = []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix"}
+
+// parseGeneration reads a generation as iii, 3 or generation-iii
+func parseGeneration(value string) (string, error) {
+	numeral := strings.TrimPrefix(value, "generation-")
+	if number, err := strconv.Atoi(numeral); err == nil && number >= 1 && number <= len(romanNumerals) {
+		numeral = romanNumerals[number-1]
+	}
+	if !slices.Contains(romanNumerals, numeral) {
+		return "", fmt.Errorf("generation must be a number from 1 to %d, or i to %s", len(romanNumerals), romanNumerals[len(romanNumerals)-1])
+	}
+	return "generation-" + numeral, nil
+}
+
+// colorMode is how many colors the terminal can show
+type colorMode string
+
+const (
+	colorTrue  colorMode = "truecolor"
+	color256   colorMode = "256"
+	colorASCII colorMode = "ascii"
+)
+
+// detectColors works out the terminal's colors from the environment, the
+// way most terminal programs do
+func detectColors(getenv func(string) string) colorMode {
+	term := getenv("TERM")
+	switch {
+	case getenv("NO_COLOR") != "" || term == "" || term == "dumb":
+		return colorASCII
+	case getenv("COLORTERM") == "truecolor" || getenv("COLORTERM") == "24bit":
+		return colorTrue
+	case strings.Contains(term, "256color"):
+		return color256
+	}
+	return colorASCII
+}
+
+func parseColorMode(value string) (colorMode, error) {
+	for _, mode := range []colorMode{colorTrue, color256, colorASCII} {
+		if value == string(mode) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("colors must be %s, %s or %s", colorTrue, color256, colorASCII)
+}
+
+// opaqueBounds is the part of an image with anything drawn in it, sprites
+// have a wide transparent border
+func opaqueBounds(img image.Image) image.Rectangle {
+	bounds := image.Rectangle{}
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if opaque(img.At(x, y)) {
+				bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return bounds
+}
+
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a >= 0x8000
+}
+
+// ansiColor is the escape sequence for a foreground (38) or background
+// (48) color
+func ansiColor(layer int, c color.Color, mode colorMode) string {
+	rgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if mode == colorTrue {
+		return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", layer, rgba.R, rgba.G, rgba.B)
+	}
+	return fmt.Sprintf("\x1b[%d;5;%dm", layer, xterm256(rgba))
+}
+
+// xterm256 is the nearest color of the 256 color palette, from the gray
+// ramp for grays and the 6x6x6 cube for the rest
+func xterm256(c color.NRGBA) int {
+	if c.R == c.G && c.G == c.B {
+		switch {
+		case c.R < 8:
+			return 16
+		case c.R > 248:
+			return 231
+		}
+		return 232 + (int(c.R)-8)*24/241
+	}
+	level := func(v uint8) int {
+		return (int(v)*5 + 127) / 255
+	}
+	return 16 + 36*level(c.R) + 6*level(c.G) + level(c.B)
+}
+
+// asciiRamp goes from light to dark, for terminals without colors
+const asciiRamp = " .:-=+*#%@"
+
+func asciiShade(c color.Color) byte {
+	rgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	luma := (299*int(rgba.R) + 587*int(rgba.G) + 114*int(rgba.B)) / 1000
+	// darker pixels get denser characters, skipping the blank
+	return asciiRamp[1+(255-luma)*(len(asciiRamp)-2)/255]
+}
+
+// renderSprite draws an image in the terminal. with colors every character
+// is a half block showing two pixels, the top one in the foreground and the
+// bottom one in the background. without colors every character is one
+// pixel shaded by brightness, skipping every other row to keep the
+// proportions
+func renderSprite(img image.Image, mode colorMode) string {
+	bounds := opaqueBounds(img)
+	var b strings.Builder
+	if mode == colorASCII {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				pixel := img.At(x, y)
+				shade := byte(' ')
+				if opaque(pixel) {
+					shade = asciiShade(pixel)
+				}
+				b.WriteByte(shade)
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.At(x, y)
+			bottom := color.Color(color.Transparent)
+			if y+1 < bounds.Max.Y {
+				bottom = img.At(x, y+1)
+			}
+			switch {
+			case opaque(top) && opaque(bottom):
+				b.WriteString(ansiColor(38, top, mode) + ansiColor(48, bottom, mode) + "▀\x1b[0m")
+			case opaque(top):
+				b.WriteString(ansiColor(38, top, mode) + "▀\x1b[0m")
+			case opaque(bottom):
+				b.WriteString(ansiColor(38, bottom, mode) + "▄\x1b[0m")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// spriteArt downloads a sprite through the client's cache and draws it
+func (s *session) spriteArt(url string, mode colorMode) (string, error) {
+	data, err := s.client.getRaw(url)
+	if err != nil {
+		return "", err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("decoding sprite %s: %w", url, err)
+	}
+	return renderSprite(img, mode), nil
+}
+
+type spriteResult struct {
+	Pokemon    string `json:"pokemon"`
+	Url        string `json:"url"`
+	Shiny      bool   `json:"shiny,omitempty"`
+	Back       bool   `json:"back,omitempty"`
+	Generation string `json:"generation,omitempty"`
+	// Art is only drawn for text output, the other formats carry the url
+	Art string `json:"-"`
+}
+
+func (r spriteResult) renderText(w io.Writer) {
+	fmt.Fprint(w, r.Art)
+}
+
+func (r spriteResult) pipeValues() []string {
+	return []string{r.Url}
+}
+
+func (r spriteResult) tableRows() ([]string, [][]string) {
+	return fieldRows(
+		"pokemon", r.Pokemon,
+		"url", r.Url,
+		"shiny", strconv.FormatBool(r.Shiny),
+		"back", strconv.FormatBool(r.Back),
+		"generation", r.Generation,
+	)
+}
+
+func commandSprite(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"gen", "colors"}, []string{"shiny", "back"})
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("sprite command requires a pokemon name, or the id or nickname of a caught pokemon")
+	}
+	opts := spriteOptions{
+		version:      s.version,
+		versionGroup: s.versionGroup,
+		shiny:        flags["shiny"] != "",
+		back:         flags["back"] != "",
+	}
+	if value, ok := flags["gen"]; ok {
+		if opts.generation, err = parseGeneration(value); err != nil {
+			return nil, err
+		}
+	}
+	mode := s.colors
+	if value, ok := flags["colors"]; ok {
+		if mode, err = parseColorMode(value); err != nil {
+			return nil, err
+		}
+	}
+
+	// a caught pokemon is drawn the way it looks, shiny or not
+	name := args[0]
+	if caught, err := s.findCaught(name); err == nil {
+		name = caught.Name
+		opts.shiny = opts.shiny || caught.Shiny
+	}
+	pokemon, err := s.client.getPokemon(name)
+	if err != nil {
+		return nil, err
+	}
+	url := pokemon.spriteUrl(opts)
+	if url == "" {
+		kind := strings.ReplaceAll(opts.field(), "_", " ")
+		if opts.generation != "" {
+			return nil, fmt.Errorf("%s has no %s sprite in %s", pokemon.Name, kind, opts.generation)
+		}
+		return nil, fmt.Errorf("%s has no %s sprite", pokemon.Name, kind)
+	}
+
+	result := spriteResult{
+		Pokemon:    pokemon.Name,
+		Url:        url,
+		Shiny:      opts.shiny,
+		Back:       opts.back,
+		Generation: opts.generation,
+	}
+	if s.output == formatText {
+		if result.Art, err = s.spriteArt(url, mode); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSpriteUrlFollowsVersion(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	pokemon, err := s.client.getPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cases := []struct {
+		opts     spriteOptions
+		expected string
+	}{
+		{spriteOptions{}, "sprites/pokemon/25.png"},
+		{spriteOptions{version: "diamond", versionGroup: "diamond-pearl"}, "versions/generation-iv/diamond-pearl/25.png"},
+		{spriteOptions{version: "platinum", versionGroup: "platinum"}, "versions/generation-iv/platinum/25.png"},
+		{spriteOptions{version: "red", versionGroup: "red-blue"}, "versions/generation-i/red-blue/25.png"},
+		{spriteOptions{version: "x", versionGroup: "x-y"}, "sprites/pokemon/25.png"},
+		{spriteOptions{shiny: true}, "sprites/pokemon/shiny/25.png"},
+		{spriteOptions{version: "platinum", versionGroup: "platinum", shiny: true}, "versions/generation-iv/platinum/shiny/25.png"},
+		// generation i had no shiny pokemon, so there is no sprite for it
+		{spriteOptions{version: "red", versionGroup: "red-blue", shiny: true}, "sprites/pokemon/shiny/25.png"},
+		{spriteOptions{back: true}, "sprites/pokemon/back/25.png"},
+		{spriteOptions{back: true, shiny: true, version: "diamond", versionGroup: "diamond-pearl"}, "versions/generation-iv/diamond-pearl/back/shiny/25.png"},
+		{spriteOptions{generation: "generation-iv"}, "versions/generation-iv/diamond-pearl/25.png"},
+		{spriteOptions{generation: "generation-iv", version: "platinum", versionGroup: "platinum"}, "versions/generation-iv/platinum/25.png"},
+		{spriteOptions{generation: "generation-i", version: "platinum", versionGroup: "platinum"}, "versions/generation-i/red-blue/25.png"},
+	}
+	for _, c := range cases {
+		if url := pokemon.spriteUrl(c.opts); !strings.HasSuffix(url, c.expected) {
+			t.Errorf("%+v: expected a url ending in %s, got %s", c.opts, c.expected, url)
+		}
+	}
+	for _, opts := range []spriteOptions{{generation: "generation-iii"}, {generation: "generation-i", shiny: true}} {
+		if url := pokemon.spriteUrl(opts); url != "" {
+			t.Errorf("%+v: expected no sprite, got %s", opts, url)
+		}
+	}
+}
+
+func TestParseGeneration(t *testing.T) {
+	for _, value := range []string{"iii", "3", "generation-iii"} {
+		if generation, err := parseGeneration(value); err != nil || generation != "generation-iii" {
+			t.Errorf("%s: expected generation-iii, got %q and %v", value, generation, err)
+		}
+	}
+	for _, value := range []string{"0", "10", "x", ""} {
+		if _, err := parseGeneration(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
+
+func TestDetectColors(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected colorMode
+	}{
+		{map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, colorTrue},
+		{map[string]string{"TERM": "xterm-256color"}, color256},
+		{map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, colorASCII},
+		{map[string]string{"TERM": "dumb"}, colorASCII},
+		{map[string]string{}, colorASCII},
+	}
+	for _, c := range cases {
+		getenv := func(key string) string { return c.env[key] }
+		if mode := detectColors(getenv); mode != c.expected {
+			t.Errorf("%v: expected %s, got %s", c.env, c.expected, mode)
+		}
+	}
+}
+
+func TestXterm256(t *testing.T) {
+	cases := map[color.NRGBA]int{
+		{0, 0, 0, 255}:       16,
+		{255, 255, 255, 255}: 231,
+		{255, 0, 0, 255}:     196,
+		{0, 0, 255, 255}:     21,
+		{128, 128, 128, 255}: 243,
+	}
+	for c, expected := range cases {
+		if index := xterm256(c); index != expected {
+			t.Errorf("%v: expected %d, got %d", c, expected, index)
+		}
+	}
+}
+
+// testSprite is a 4x4 image with a transparent border around a red pixel
+// over a black one
+func testSprite() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.NRGBA{255, 0, 0, 255})
+	img.Set(1, 2, color.NRGBA{0, 0, 0, 255})
+	img.Set(2, 2, color.NRGBA{255, 255, 255, 255})
+	return img
+}
+
+func TestRenderSprite(t *testing.T) {
+	cases := map[colorMode]string{
+		colorTrue:  "\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m▀\x1b[0m\x1b[38;2;255;255;255m▄\x1b[0m\n",
+		color256:   "\x1b[38;5;196m\x1b[48;5;16m▀\x1b[0m\x1b[38;5;231m▄\x1b[0m\n",
+		colorASCII: "* \n",
+	}
+	for mode, expected := range cases {
+		if art := renderSprite(testSprite(), mode); art != expected {
+			t.Errorf("%s: expected %q, got %q", mode, expected, art)
+		}
+	}
+}
+
+func TestSpriteCommand(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	var data bytes.Buffer
+	if err := png.Encode(&data, testSprite()); err != nil {
+		t.Fatalf("encoding sprite: %v", err)
+	}
+	// the sprites live on github, the cache stands in for the download
+	s.client.cache.Add("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/versions/generation-iv/diamond-pearl/back/shiny/25.png", data.Bytes())
+	s.client.cache.Add("https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png", data.Bytes())
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+
+	lines := []string{
+		"sprite pikachu --shiny --back --gen 4 --colors 256",
+		"sprite pikachu --gen iii",
+		"inspect 1 --sprite",
+	}
+	s.colors = colorASCII
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !strings.HasPrefix(out.String(), "\x1b[38;5;196m\x1b[48;5;16m▀") {
+		t.Errorf("expected the sprite in 256 colors, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "Sprite: https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png\n* \n") {
+		t.Errorf("expected inspect to draw the sprite in ascii, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "pikachu has no front default sprite in generation-iii") {
+		t.Errorf("expected a missing sprite error, got %q", errOut.String())
+	}
+}
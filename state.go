@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// saveVersion is the current layout of the save file. when the layout
+// changes, bump it and add a migration from the previous version
+const saveVersion = 2
+
+// saveMigrations upgrade a decoded save file from the version they are keyed
+// by to the next one
+var saveMigrations = map[int]func(save map[string]json.RawMessage) error{
+	1: migrateToStorage,
+}
+
+// gameState is everything that is kept between runs
+type gameState struct {
+	Version   int                      `json:"version"`
+	SavedAt   time.Time                `json:"saved_at"`
+	Pokedex   map[string]*pokedexEntry `json:"pokedex"`
+	Pokemon   []caughtPokemon          `json:"pokemon"`
+	Party     []int                    `json:"party"`
+	Boxes     [][]int                  `json:"boxes"`
+	NextID    int                      `json:"next_id"`
+	Inventory map[string]int           `json:"inventory"`
+	MapPages  mapPagesState            `json:"map_pages"`
+	Location  string                   `json:"location,omitempty"`
+	Settings  settingsState            `json:"settings"`
+	Stats     trainerStats             `json:"stats"`
+}
+
+type mapPagesState struct {
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+}
+
+type settingsState struct {
+	Output       outputFormat `json:"output"`
+	Sandbox      bool         `json:"sandbox"`
+	Version      string       `json:"version,omitempty"`
+	VersionGroup string       `json:"version_group,omitempty"`
+	ShinyOdds    int          `json:"shiny_odds,omitempty"`
+}
+
+// snapshot captures the session's game state for saving
+func (s *session) snapshot() gameState {
+	return gameState{
+		Version:   saveVersion,
+		SavedAt:   s.now(),
+		Pokedex:   s.pokedex.entries,
+		Pokemon:   s.caughtByID(),
+		Party:     s.party,
+		Boxes:     s.boxes,
+		NextID:    s.nextID,
+		Inventory: s.inventory,
+		MapPages:  mapPagesState{Next: s.indexUrls.nextUrl, Previous: s.indexUrls.prevUrl},
+		Location:  s.location,
+		Settings: settingsState{
+			Output:       s.output,
+			Sandbox:      s.sandbox,
+			Version:      s.version,
+			VersionGroup: s.versionGroup,
+			ShinyOdds:    s.shinyOdds,
+		},
+		Stats: s.stats,
+	}
+}
+
+// restore replaces the session's game state with a loaded one
+func (s *session) restore(state gameState) {
+	s.pokedex = newPokedex()
+	if state.Pokedex != nil {
+		s.pokedex.entries = state.Pokedex
+	}
+	s.caught = map[int]caughtPokemon{}
+	s.nextID = state.NextID
+	for _, pokemon := range state.Pokemon {
+		s.caught[pokemon.ID] = pokemon
+		s.nextID = max(s.nextID, pokemon.ID)
+	}
+	s.party = []int{}
+	if state.Party != nil {
+		s.party = state.Party
+	}
+	s.boxes = emptyBoxes()
+	for i, box := range state.Boxes {
+		if i < boxCount && box != nil {
+			s.boxes[i] = box
+		}
+	}
+	s.inventory = startingInventory()
+	if state.Inventory != nil {
+		s.inventory = state.Inventory
+	}
+	s.indexUrls = config{nextUrl: state.MapPages.Next, prevUrl: state.MapPages.Previous}
+	s.location = state.Location
+	s.sandbox = state.Settings.Sandbox
+	s.version = state.Settings.Version
+	s.versionGroup = state.Settings.VersionGroup
+	s.shinyOdds = state.Settings.ShinyOdds
+	s.output = formatText
+	if state.Settings.Output != "" {
+		s.output = state.Settings.Output
+	}
+	s.stats = state.Stats
+	// a wild pokemon or quiz round belongs to the trainer that met it
+	s.wild = nil
+	s.quiz = nil
+}
+
+// caughtByID lists the caught pokemon in the order they were caught
+func (s *session) caughtByID() []caughtPokemon {
+	pokemon := []caughtPokemon{}
+	for _, id := range slices.Sorted(maps.Keys(s.caught)) {
+		pokemon = append(pokemon, s.caught[id])
+	}
+	return pokemon
+}
+
+// migrateToStorage moves version 1 saves, where there was one pokemon per
+// name and a lead, to pokemon with ids in a party and boxes. the lead goes
+// first and the rest follow by name
+func migrateToStorage(save map[string]json.RawMessage) error {
+	caught := map[string]caughtPokemon{}
+	if raw, ok := save["caught"]; ok {
+		if err := json.Unmarshal(raw, &caught); err != nil {
+			return err
+		}
+	}
+	lead := ""
+	if raw, ok := save["lead"]; ok {
+		if err := json.Unmarshal(raw, &lead); err != nil {
+			return err
+		}
+	}
+	names := sortedKeys(caught)
+	if _, exists := caught[lead]; exists {
+		names = slices.DeleteFunc(names, func(name string) bool { return name == lead })
+		names = slices.Insert(names, 0, lead)
+	}
+
+	pokemon := []caughtPokemon{}
+	party := []int{}
+	boxes := emptyBoxes()
+	for i, name := range names {
+		entry := caught[name]
+		entry.ID = i + 1
+		// version 1 had no limit, pokemon that do not fit are kept in the
+		// last box past its size rather than making the save unloadable
+		if place(entry.ID, &party, boxes) < 0 {
+			boxes[len(boxes)-1] = append(boxes[len(boxes)-1], entry.ID)
+		}
+		pokemon = append(pokemon, entry)
+	}
+	for key, value := range map[string]any{"pokemon": pokemon, "party": party, "boxes": boxes, "next_id": len(names)} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		save[key] = data
+	}
+	delete(save, "caught")
+	delete(save, "lead")
+	return nil
+}
+
+// saveGame writes the game state to the session's save file, going through
+// a temporary file so a crash can not leave a half written save behind
+func (s *session) saveGame() error {
+	if s.savePath == "" {
+		return fmt.Errorf("this session has no save file")
+	}
+	data, err := json.MarshalIndent(s.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.savePath), 0755); err != nil {
+		return err
+	}
+	tmpPath := s.savePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.savePath)
+}
+
+// loadGame reads the session's save file. it returns fs.ErrNotExist when
+// there is nothing saved yet
+func (s *session) loadGame() error {
+	if s.savePath == "" {
+		return fmt.Errorf("this session has no save file")
+	}
+	data, err := os.ReadFile(s.savePath)
+	if err != nil {
+		return err
+	}
+	state, err := decodeSave(data)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", s.savePath, err)
+	}
+	s.restore(state)
+	return nil
+}
+
+// decodeSave migrates a save file to the current version before decoding it
+func decodeSave(data []byte) (gameState, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return gameState{}, err
+	}
+	version := 0
+	if rawVersion, ok := raw["version"]; ok {
+		if err := json.Unmarshal(rawVersion, &version); err != nil {
+			return gameState{}, fmt.Errorf("invalid version: %w", err)
+		}
+	}
+	if version > saveVersion {
+		return gameState{}, fmt.Errorf("save file version %d is newer than this pokedex supports (%d)", version, saveVersion)
+	}
+	for ; version < saveVersion; version++ {
+		migrate, ok := saveMigrations[version]
+		if !ok {
+			return gameState{}, fmt.Errorf("no migration from save file version %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return gameState{}, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+	}
+	raw["version"] = json.RawMessage(fmt.Sprint(saveVersion))
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return gameState{}, err
+	}
+	var state gameState
+	err = json.Unmarshal(migrated, &state)
+	return state, err
+}
+
+func commandSave(s *session, params ...string) (commandResult, error) {
+	if len(params) != 0 {
+		return nil, fmt.Errorf("save command does not take any parameters")
+	}
+	if err := s.saveGame(); err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("Game saved to %s", s.savePath)}, nil
+}
+
+func commandLoad(s *session, params ...string) (commandResult, error) {
+	if len(params) != 0 {
+		return nil, fmt.Errorf("load command does not take any parameters")
+	}
+	err := s.loadGame()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("there is no saved game yet")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("Game loaded from %s", s.savePath)}, nil
+}
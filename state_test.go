@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	s, _, errOut := newTestSession(t)
+	s.savePath = filepath.Join(t.TempDir(), "save.json")
+	err := s.run(strings.NewReader("map\ncatch magikarp --ball master\nset output yaml\nexit\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("unexpected errors: %s", errOut.String())
+	}
+
+	loaded, _, _ := newTestSession(t)
+	loaded.savePath = s.savePath
+	if err := loaded.loadGame(); err != nil {
+		t.Fatalf("expected the game to be saved on exit: %v", err)
+	}
+	if _, err := loaded.findCaught("magikarp"); err != nil {
+		t.Errorf("expected magikarp to be loaded")
+	}
+	if entry, ok := loaded.pokedex.entry("magikarp"); !ok || !entry.caught() {
+		t.Errorf("expected the pokedex entry to be loaded")
+	}
+	if loaded.inventory["master-ball"] != 0 || loaded.inventory["poke-ball"] != 20 {
+		t.Errorf("unexpected inventory %v", loaded.inventory)
+	}
+	if loaded.indexUrls.nextUrl == nil || !strings.HasSuffix(*loaded.indexUrls.nextUrl, "offset=20&limit=20") {
+		t.Errorf("expected the map page to be loaded")
+	}
+	if loaded.output != formatYAML {
+		t.Errorf("expected the output setting to be loaded, got %s", loaded.output)
+	}
+}
+
+func TestInterruptSavesGame(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.savePath = filepath.Join(t.TempDir(), "save.json")
+	interrupts := make(chan os.Signal, 1)
+	s.interrupts = interrupts
+
+	// the input stays open, like a terminal waiting for the next line
+	reader, writer := io.Pipe()
+	t.Cleanup(func() { writer.Close() })
+	done := make(chan error)
+	go func() { done <- s.run(reader) }()
+	// the reader takes a line at a time, once the third is read the catch
+	// has run
+	for _, line := range []string{"catch magikarp --ball master\n", "\n", "\n"} {
+		if _, err := io.WriteString(writer, line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	interrupts <- os.Interrupt
+	if err := <-done; !errors.Is(err, errInterrupted) {
+		t.Fatalf("expected the run to be interrupted, got %v", err)
+	}
+
+	loaded, _, _ := newTestSession(t)
+	loaded.savePath = s.savePath
+	if err := loaded.loadGame(); err != nil {
+		t.Fatalf("expected the game to be saved on interrupt: %v", err)
+	}
+	if _, err := loaded.findCaught("magikarp"); err != nil {
+		t.Errorf("expected magikarp to be saved")
+	}
+}
+
+func TestInterruptDuringCommand(t *testing.T) {
+	s, _, errOut := newTestSession(t)
+	// the shakes keep the catch going while the interrupts come in
+	s.shakeDelay = 50 * time.Millisecond
+	interrupts := make(chan os.Signal, 2)
+	s.interrupts = interrupts
+	quits := make(chan int, 1)
+	s.quit = func(code int) { quits <- code }
+
+	interrupts <- os.Interrupt
+	interrupted, err := s.runWatched("catch magikarp --ball master")
+	if err != nil || !interrupted {
+		t.Fatalf("expected the command to finish and stop the repl, got %v and %v", interrupted, err)
+	}
+	if _, err := s.findCaught("magikarp"); err != nil || len(quits) != 0 {
+		t.Errorf("expected the catch to finish without quitting")
+	}
+	if !strings.Contains(errOut.String(), "press ctrl-c again to quit without saving") {
+		t.Errorf("expected to be told how to quit, got %q", errOut.String())
+	}
+
+	s.inventory["master-ball"] = 1
+	interrupts <- os.Interrupt
+	interrupts <- os.Interrupt
+	if _, err := s.runWatched("catch magikarp --ball master"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if code := <-quits; code != 130 {
+		t.Errorf("expected a second interrupt to quit with 130, got %d", code)
+	}
+}
+
+func TestLoadWithoutSave(t *testing.T) {
+	s, _, errOut := newTestSession(t)
+	s.savePath = filepath.Join(t.TempDir(), "save.json")
+	if err := s.runLine("load"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "there is no saved game yet") {
+		t.Errorf("unexpected output %q", errOut.String())
+	}
+}
+
+func TestDecodeSaveVersions(t *testing.T) {
+	if _, err := decodeSave([]byte(`{"version": 999}`)); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("expected an error for a newer save file, got %v", err)
+	}
+	if _, err := decodeSave([]byte(`{"version": 0}`)); err == nil || !strings.Contains(err.Error(), "no migration") {
+		t.Errorf("expected an error for a missing migration, got %v", err)
+	}
+
+	// a fake version 0 that called the inventory "bag"
+	saveMigrations[0] = func(save map[string]json.RawMessage) error {
+		save["inventory"] = save["bag"]
+		delete(save, "bag")
+		return nil
+	}
+	defer delete(saveMigrations, 0)
+	state, err := decodeSave([]byte(`{"version": 0, "bag": {"poke-ball": 3}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state.Version != saveVersion || state.Inventory["poke-ball"] != 3 {
+		t.Errorf("expected the save to be migrated, got %+v", state)
+	}
+}
+
+func TestSaveIsWrittenAtomically(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.savePath = filepath.Join(t.TempDir(), "nested", "save.json")
+	if err := s.saveGame(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(s.savePath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be gone")
+	}
+}
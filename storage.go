@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	partySize = 6
+	boxSize   = 30
+	boxCount  = 8
+)
+
+// emptyBoxes returns the pc's boxes with nothing in them
+func emptyBoxes() [][]int {
+	boxes := make([][]int, boxCount)
+	for i := range boxes {
+		boxes[i] = []int{}
+	}
+	return boxes
+}
+
+// place puts a pokemon id in the party when there is room, otherwise in the
+// first box with room. it returns the box number, 0 for the party, or -1
+// when everything is full
+func place(id int, party *[]int, boxes [][]int) int {
+	if len(*party) < partySize {
+		*party = append(*party, id)
+		return 0
+	}
+	for i := range boxes {
+		if len(boxes[i]) < boxSize {
+			boxes[i] = append(boxes[i], id)
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// hasRoom reports whether there is space for another caught pokemon
+func (s *session) hasRoom() bool {
+	if len(s.party) < partySize {
+		return true
+	}
+	for _, box := range s.boxes {
+		if len(box) < boxSize {
+			return true
+		}
+	}
+	return false
+}
+
+// store gives a newly caught pokemon its id and puts it away, returning
+// the stored pokemon and the box number it went into (0 for the party)
+func (s *session) store(pokemon caughtPokemon) (caughtPokemon, int, error) {
+	if !s.hasRoom() {
+		return caughtPokemon{}, 0, fmt.Errorf("your party and boxes are full, release some pokemon first")
+	}
+	s.nextID++
+	pokemon.ID = s.nextID
+	s.caught[pokemon.ID] = pokemon
+	return pokemon, place(pokemon.ID, &s.party, s.boxes), nil
+}
+
+// locate finds which box a pokemon is in, 0 for the party
+func (s *session) locate(id int) int {
+	if slices.Contains(s.party, id) {
+		return 0
+	}
+	for i, box := range s.boxes {
+		if slices.Contains(box, id) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// unstore takes a pokemon out of the party or whichever box it is in
+func (s *session) unstore(id int) {
+	s.party = slices.DeleteFunc(s.party, func(other int) bool { return other == id })
+	for i := range s.boxes {
+		s.boxes[i] = slices.DeleteFunc(s.boxes[i], func(other int) bool { return other == id })
+	}
+}
+
+// ordered lists the caught pokemon the way the trainer sees them, the
+// party first and then box by box
+func (s *session) ordered() []caughtPokemon {
+	pokemon := []caughtPokemon{}
+	for _, id := range s.party {
+		pokemon = append(pokemon, s.caught[id])
+	}
+	for _, box := range s.boxes {
+		for _, id := range box {
+			pokemon = append(pokemon, s.caught[id])
+		}
+	}
+	return pokemon
+}
+
+// findCaught looks a caught pokemon up by its id, or by name or nickname
+// when only one of the trainer's pokemon goes by it
+func (s *session) findCaught(ref string) (caughtPokemon, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		pokemon, exists := s.caught[id]
+		if !exists {
+			return caughtPokemon{}, fmt.Errorf("you have no pokemon with id %d", id)
+		}
+		return pokemon, nil
+	}
+	matches := []caughtPokemon{}
+	for _, pokemon := range s.ordered() {
+		// nicknames keep the case they were given in, but match in any
+		if strings.EqualFold(pokemon.Name, ref) || strings.EqualFold(pokemon.Nickname, ref) {
+			matches = append(matches, pokemon)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return caughtPokemon{}, fmt.Errorf("you have not caught %s", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return caughtPokemon{}, fmt.Errorf("you have %d %s, use an id instead (see party and box)", len(matches), ref)
+}
+
+// lead is the first pokemon in the party, the one that earns experience
+// from wild pokemon
+func (s *session) lead() (caughtPokemon, bool) {
+	if len(s.party) == 0 {
+		return caughtPokemon{}, false
+	}
+	return s.caught[s.party[0]], true
+}
+
+// leadName names the lead pokemon for settings, empty without a party
+func (s *session) leadName() string {
+	lead, exists := s.lead()
+	if !exists {
+		return ""
+	}
+	return fmt.Sprintf("#%d %s", lead.ID, lead.Name)
+}
+
+// setLead moves a pokemon in the party to the front of it
+func (s *session) setLead(ref string) error {
+	pokemon, err := s.findCaught(ref)
+	if err != nil {
+		return err
+	}
+	if box := s.locate(pokemon.ID); box != 0 {
+		return fmt.Errorf("%s is in box %d, withdraw it first", pokemon.Name, box)
+	}
+	s.party = slices.DeleteFunc(s.party, func(id int) bool { return id == pokemon.ID })
+	s.party = slices.Insert(s.party, 0, pokemon.ID)
+	return nil
+}
+
+// storedPokemon is how a pokemon is listed in the party and boxes
+type storedPokemon struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname,omitempty"`
+	Level    int    `json:"level"`
+	Shiny    bool   `json:"shiny,omitempty"`
+}
+
+func (p storedPokemon) String() string {
+	details := fmt.Sprintf("level %d", p.Level)
+	if p.Nickname != "" {
+		details = fmt.Sprintf("%s, %s", p.Name, details)
+	}
+	if p.Shiny {
+		details += ", shiny"
+	}
+	return fmt.Sprintf("#%d %s (%s)", p.ID, p.displayName(), details)
+}
+
+func (p storedPokemon) displayName() string {
+	if p.Nickname != "" {
+		return p.Nickname
+	}
+	return p.Name
+}
+
+func (s *session) listStored(ids []int) []storedPokemon {
+	list := []storedPokemon{}
+	for _, id := range ids {
+		pokemon := s.caught[id]
+		list = append(list, storedPokemon{ID: id, Name: pokemon.Name, Nickname: pokemon.Nickname, Level: pokemon.level(), Shiny: pokemon.Shiny})
+	}
+	return list
+}
+
+// boxContents is the party (box 0) or one of the pc's boxes
+type boxContents struct {
+	Box     int             `json:"box"`
+	Pokemon []storedPokemon `json:"pokemon"`
+}
+
+func (b boxContents) title() string {
+	if b.Box == 0 {
+		return fmt.Sprintf("Party (%d/%d)", len(b.Pokemon), partySize)
+	}
+	return fmt.Sprintf("Box %d (%d/%d)", b.Box, len(b.Pokemon), boxSize)
+}
+
+type storageResult struct {
+	Boxes []boxContents `json:"boxes"`
+}
+
+func (r storageResult) renderText(w io.Writer) {
+	for _, box := range r.Boxes {
+		fmt.Fprintf(w, "%s:\n", box.title())
+		for _, pokemon := range box.Pokemon {
+			fmt.Fprintf(w, " %s\n", pokemon)
+		}
+	}
+}
+
+func (r storageResult) pipeValues() []string {
+	ids := []string{}
+	for _, box := range r.Boxes {
+		for _, pokemon := range box.Pokemon {
+			ids = append(ids, strconv.Itoa(pokemon.ID))
+		}
+	}
+	return ids
+}
+
+func (r storageResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, box := range r.Boxes {
+		where := "party"
+		if box.Box > 0 {
+			where = fmt.Sprintf("box %d", box.Box)
+		}
+		for _, pokemon := range box.Pokemon {
+			rows = append(rows, []string{strconv.Itoa(pokemon.ID), pokemon.Name, pokemon.Nickname, strconv.Itoa(pokemon.Level), strconv.FormatBool(pokemon.Shiny), where})
+		}
+	}
+	return []string{"ID", "POKEMON", "NICKNAME", "LEVEL", "SHINY", "WHERE"}, rows
+}
+
+func commandParty(s *session, params ...string) (commandResult, error) {
+	if len(params) != 0 {
+		return nil, fmt.Errorf("party command does not take any parameters")
+	}
+	return storageResult{Boxes: []boxContents{{Box: 0, Pokemon: s.listStored(s.party)}}}, nil
+}
+
+// parseBox reads a box number as the trainer types it, counting from 1
+func parseBox(value string) (int, error) {
+	box, err := strconv.Atoi(value)
+	if err != nil || box < 1 || box > boxCount {
+		return 0, fmt.Errorf("box must be a number from 1 to %d", boxCount)
+	}
+	return box, nil
+}
+
+func commandBox(s *session, params ...string) (commandResult, error) {
+	if len(params) > 1 {
+		return nil, fmt.Errorf("box command takes at most a box number")
+	}
+	result := storageResult{Boxes: []boxContents{}}
+	if len(params) == 1 {
+		box, err := parseBox(params[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Boxes = append(result.Boxes, boxContents{Box: box, Pokemon: s.listStored(s.boxes[box-1])})
+		return result, nil
+	}
+	// without a number only the boxes with something in them are listed
+	for i, box := range s.boxes {
+		if len(box) > 0 {
+			result.Boxes = append(result.Boxes, boxContents{Box: i + 1, Pokemon: s.listStored(box)})
+		}
+	}
+	return result, nil
+}
+
+func commandDeposit(s *session, params ...string) (commandResult, error) {
+	args, flags, err := parseFlags(params, []string{"box"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("deposit command requires the id or name of a pokemon in your party")
+	}
+	pokemon, err := s.findCaught(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if s.locate(pokemon.ID) != 0 {
+		return nil, fmt.Errorf("%s is not in your party", pokemon.Name)
+	}
+	if len(s.party) == 1 {
+		return nil, fmt.Errorf("you can not deposit the last pokemon in your party")
+	}
+
+	box := 0
+	if value, ok := flags["box"]; ok {
+		if box, err = parseBox(value); err != nil {
+			return nil, err
+		}
+		if len(s.boxes[box-1]) >= boxSize {
+			return nil, fmt.Errorf("box %d is full", box)
+		}
+	} else {
+		for i := range s.boxes {
+			if len(s.boxes[i]) < boxSize {
+				box = i + 1
+				break
+			}
+		}
+		if box == 0 {
+			return nil, fmt.Errorf("all of your boxes are full")
+		}
+	}
+	s.unstore(pokemon.ID)
+	s.boxes[box-1] = append(s.boxes[box-1], pokemon.ID)
+	return messageResult{Message: fmt.Sprintf("#%d %s was put in box %d", pokemon.ID, pokemon.Name, box)}, nil
+}
+
+func commandWithdraw(s *session, params ...string) (commandResult, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("withdraw command requires the id or name of a pokemon in a box")
+	}
+	pokemon, err := s.findCaught(params[0])
+	if err != nil {
+		return nil, err
+	}
+	if s.locate(pokemon.ID) == 0 {
+		return nil, fmt.Errorf("%s is already in your party", pokemon.Name)
+	}
+	if len(s.party) >= partySize {
+		return nil, fmt.Errorf("your party is full, deposit a pokemon first")
+	}
+	s.unstore(pokemon.ID)
+	s.party = append(s.party, pokemon.ID)
+	return messageResult{Message: fmt.Sprintf("#%d %s joined your party", pokemon.ID, pokemon.Name)}, nil
+}
+
+func commandRelease(s *session, params ...string) (commandResult, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("release command requires the id or name of a caught pokemon")
+	}
+	pokemon, err := s.findCaught(params[0])
+	if err != nil {
+		return nil, err
+	}
+	if s.locate(pokemon.ID) == 0 && len(s.party) == 1 {
+		return nil, fmt.Errorf("you can not release the last pokemon in your party")
+	}
+	s.unstore(pokemon.ID)
+	delete(s.caught, pokemon.ID)
+	return messageResult{Message: fmt.Sprintf("#%d %s was released. Bye, %s!", pokemon.ID, pokemon.Name, pokemon.Name)}, nil
+}
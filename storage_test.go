@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPartyAndBoxes(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	for i := 0; i < partySize+2; i++ {
+		if _, _, err := s.store(caughtPokemon{Name: "magikarp", CaughtAt: s.now()}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(s.party, []int{1, 2, 3, 4, 5, 6}) || !reflect.DeepEqual(s.boxes[0], []int{7, 8}) {
+		t.Fatalf("expected the first six in the party and the rest in box 1, got %v and %v", s.party, s.boxes[0])
+	}
+
+	lines := []string{
+		"withdraw 7",
+		"deposit 2 --box 3",
+		"withdraw 7",
+		"release 8",
+		"set lead 7",
+		"deposit magikarp",
+		"party",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(s.party, []int{7, 1, 3, 4, 5, 6}) {
+		t.Errorf("expected 7 to lead the party, got %v", s.party)
+	}
+	if !reflect.DeepEqual(s.boxes[0], []int{}) || !reflect.DeepEqual(s.boxes[2], []int{2}) {
+		t.Errorf("expected only 2 in box 3, got %v", s.boxes)
+	}
+	if _, exists := s.caught[8]; exists {
+		t.Errorf("expected 8 to be released")
+	}
+	for _, expected := range []string{"#2 magikarp was put in box 3", "#7 magikarp joined your party", "#8 magikarp was released", "Party (6/6):\n #7 magikarp (level 10)\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	}
+	for _, expected := range []string{"your party is full, deposit a pokemon first", "you have 7 magikarp, use an id instead"} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, errOut.String())
+		}
+	}
+}
+
+func TestPartyCanNotBeEmptied(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	addTestPokemon(t, s, "pokemon-pikachu.json")
+	if _, err := commandDeposit(s, "pikachu"); err == nil {
+		t.Errorf("expected the last party pokemon to stay")
+	}
+	if _, err := commandRelease(s, "1"); err == nil {
+		t.Errorf("expected the last party pokemon to stay")
+	}
+}
+
+func TestCatchingTwoOfAKind(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	s.inventory["master-ball"] = 2
+	for i := 0; i < 2; i++ {
+		if err := s.runLine("catch magikarp --ball master"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(s.caught) != 2 || s.caught[1].Name != "magikarp" || s.caught[2].Name != "magikarp" {
+		t.Errorf("expected two magikarp with their own ids, got %v", s.caught)
+	}
+}
+
+func TestMigrateToStorage(t *testing.T) {
+	save := `{
+		"version": 1,
+		"caught": {
+			"magikarp": {"name": "magikarp", "caught_at": "2026-10-18T08:00:00Z", "level": 12},
+			"pikachu": {"name": "pikachu", "caught_at": "2026-10-17T08:00:00Z"},
+			"wingull": {"name": "wingull", "caught_at": "2026-10-16T08:00:00Z"}
+		},
+		"lead": "wingull"
+	}`
+	state, err := decodeSave([]byte(save))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := []string{}
+	for _, pokemon := range state.Pokemon {
+		names = append(names, pokemon.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"wingull", "magikarp", "pikachu"}) || state.Pokemon[1].Level != 12 {
+		t.Errorf("expected the lead first and the rest by name, got %+v", state.Pokemon)
+	}
+	if !reflect.DeepEqual(state.Party, []int{1, 2, 3}) || state.NextID != 3 || len(state.Boxes) != boxCount {
+		t.Errorf("expected everyone in the party, got %v, %d and %d boxes", state.Party, state.NextID, len(state.Boxes))
+	}
+
+	s, _, _ := newTestSession(t)
+	s.restore(state)
+	if lead, _ := s.lead(); lead.Name != "wingull" {
+		t.Errorf("expected wingull to still lead, got %q", lead.Name)
+	}
+	if stored, _, _ := s.store(caughtPokemon{Name: "shellos"}); stored.ID != 4 {
+		t.Errorf("expected new ids to carry on from the save, got %d", stored.ID)
+	}
+}
+
+func TestMigrateOverfullSave(t *testing.T) {
+	capacity := partySize + boxCount*boxSize
+	caught := map[string]caughtPokemon{}
+	for i := 0; i < capacity+4; i++ {
+		name := fmt.Sprintf("pokemon-%03d", i)
+		caught[name] = caughtPokemon{Name: name}
+	}
+	data, err := json.Marshal(map[string]any{"version": 1, "caught": caught})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state, err := decodeSave(data)
+	if err != nil {
+		t.Fatalf("expected a save with more pokemon than boxes to load, got %v", err)
+	}
+	if len(state.Pokemon) != capacity+4 || len(state.Boxes[boxCount-1]) != boxSize+4 {
+		t.Errorf("expected the extra pokemon in the last box, got %d pokemon and %d in the last box", len(state.Pokemon), len(state.Boxes[boxCount-1]))
+	}
+
+	s, _, _ := newTestSession(t)
+	s.restore(state)
+	if s.hasRoom() {
+		t.Errorf("expected no room for another pokemon")
+	}
+	if _, err := commandRelease(s, "250"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.ordered()) != capacity+3 {
+		t.Errorf("expected the overflow to be reachable, got %d pokemon", len(s.ordered()))
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type pokeType struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []pokeNamedResource `json:"double_damage_to"`
+		HalfDamageTo     []pokeNamedResource `json:"half_damage_to"`
+		NoDamageTo       []pokeNamedResource `json:"no_damage_to"`
+		DoubleDamageFrom []pokeNamedResource `json:"double_damage_from"`
+		HalfDamageFrom   []pokeNamedResource `json:"half_damage_from"`
+		NoDamageFrom     []pokeNamedResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func (c *pokeClient) getType(name string) (pokeType, error) {
+	var pokemonType pokeType
+	err := c.get(c.resourceUrl("type", name), &pokemonType)
+	return pokemonType, err
+}
+
+// multiplierAgainst is how effective a move of this type is against a
+// pokemon of one defending type
+func (t pokeType) multiplierAgainst(defending string) float64 {
+	has := func(list []pokeNamedResource) bool {
+		for _, entry := range list {
+			if entry.Name == defending {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has(t.DamageRelations.NoDamageTo):
+		return 0
+	case has(t.DamageRelations.DoubleDamageTo):
+		return 2
+	case has(t.DamageRelations.HalfDamageTo):
+		return 0.5
+	}
+	return 1
+}
+
+// effectiveness multiplies out how effective a move type is against all of
+// a defender's types, so a dual type pokemon can take x4 or x0.25
+func (s *session) effectiveness(attacking string, defending []string) (float64, error) {
+	attackType, err := s.client.getType(attacking)
+	if err != nil {
+		return 0, err
+	}
+	multiplier := 1.0
+	for _, defendingType := range defending {
+		multiplier *= attackType.multiplierAgainst(defendingType)
+	}
+	return multiplier, nil
+}
+
+// effectivenessText is the battle message for a multiplier
+func effectivenessText(multiplier float64) string {
+	switch {
+	case multiplier == 0:
+		return "no effect"
+	case multiplier > 1:
+		return "super effective"
+	case multiplier < 1:
+		return "not very effective"
+	}
+	return "normal damage"
+}
+
+func resourceNames(resources []pokeNamedResource) []string {
+	list := []string{}
+	for _, resource := range resources {
+		list = append(list, resource.Name)
+	}
+	return list
+}
+
+type typeResult struct {
+	Name          string   `json:"name"`
+	StrongAgainst []string `json:"strong_against"`
+	WeakAgainst   []string `json:"weak_against"`
+	NoEffectOn    []string `json:"no_effect_on"`
+	WeakTo        []string `json:"weak_to"`
+	ResistantTo   []string `json:"resistant_to"`
+	ImmuneTo      []string `json:"immune_to"`
+}
+
+func (r typeResult) pairs() []string {
+	return []string{
+		"attacking: super effective against", joinOrNone(r.StrongAgainst),
+		"attacking: not very effective against", joinOrNone(r.WeakAgainst),
+		"attacking: no effect on", joinOrNone(r.NoEffectOn),
+		"defending: weak to", joinOrNone(r.WeakTo),
+		"defending: resists", joinOrNone(r.ResistantTo),
+		"defending: immune to", joinOrNone(r.ImmuneTo),
+	}
+}
+
+func joinOrNone(list []string) string {
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(list, ", ")
+}
+
+func (r typeResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Type: %s\n", r.Name)
+	pairs := r.pairs()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fmt.Fprintf(w, " %s: %s\n", pairs[i], pairs[i+1])
+	}
+}
+
+func (r typeResult) tableRows() ([]string, [][]string) {
+	_, rows := fieldRows(r.pairs()...)
+	return []string{"RELATION", "TYPES"}, rows
+}
+
+func commandType(s *session, params ...string) (commandResult, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("type command requires a type name")
+	}
+	pokemonType, err := s.client.getType(params[0])
+	if err != nil {
+		return nil, err
+	}
+	relations := pokemonType.DamageRelations
+	return typeResult{
+		Name:          pokemonType.Name,
+		StrongAgainst: resourceNames(relations.DoubleDamageTo),
+		WeakAgainst:   resourceNames(relations.HalfDamageTo),
+		NoEffectOn:    resourceNames(relations.NoDamageTo),
+		WeakTo:        resourceNames(relations.DoubleDamageFrom),
+		ResistantTo:   resourceNames(relations.HalfDamageFrom),
+		ImmuneTo:      resourceNames(relations.NoDamageFrom),
+	}, nil
+}
+
+// resolveTypes takes a pokemon name, or a type name, and returns the types
+// it stands for
+func (s *session) resolveTypes(name string) ([]string, error) {
+	if pokemon, err := s.client.getPokemon(name); err == nil {
+		types := []string{}
+		for _, pokemonType := range pokemon.Types {
+			types = append(types, pokemonType.Type.Name)
+		}
+		return types, nil
+	}
+	if pokemonType, err := s.client.getType(name); err == nil {
+		return []string{pokemonType.Name}, nil
+	}
+	return nil, fmt.Errorf("%q is neither a pokemon nor a type", name)
+}
+
+type typeMultiplier struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+type matchupResult struct {
+	Attacker      string           `json:"attacker"`
+	AttackerTypes []string         `json:"attacker_types"`
+	Defender      string           `json:"defender"`
+	DefenderTypes []string         `json:"defender_types"`
+	Multipliers   []typeMultiplier `json:"multipliers"`
+}
+
+func (r matchupResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "%s (%s) attacking %s (%s):\n", r.Attacker, strings.Join(r.AttackerTypes, "/"), r.Defender, strings.Join(r.DefenderTypes, "/"))
+	for _, multiplier := range r.Multipliers {
+		fmt.Fprintf(w, " - %s moves: x%g (%s)\n", multiplier.Type, multiplier.Multiplier, effectivenessText(multiplier.Multiplier))
+	}
+}
+
+func (r matchupResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, multiplier := range r.Multipliers {
+		rows = append(rows, []string{multiplier.Type, strings.Join(r.DefenderTypes, "/"), fmt.Sprintf("x%g", multiplier.Multiplier)})
+	}
+	return []string{"MOVE TYPE", "DEFENDER", "MULTIPLIER"}, rows
+}
+
+func commandMatchup(s *session, params ...string) (commandResult, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("matchup command takes an attacker and a defender (pokemon or type names)")
+	}
+	attackerTypes, err := s.resolveTypes(params[0])
+	if err != nil {
+		return nil, err
+	}
+	defenderTypes, err := s.resolveTypes(params[1])
+	if err != nil {
+		return nil, err
+	}
+	result := matchupResult{
+		Attacker:      params[0],
+		AttackerTypes: attackerTypes,
+		Defender:      params[1],
+		DefenderTypes: defenderTypes,
+		Multipliers:   []typeMultiplier{},
+	}
+	for _, attackType := range attackerTypes {
+		multiplier, err := s.effectiveness(attackType, defenderTypes)
+		if err != nil {
+			return nil, err
+		}
+		result.Multipliers = append(result.Multipliers, typeMultiplier{Type: attackType, Multiplier: multiplier})
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEffectiveness(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	cases := []struct {
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{"electric", []string{"water"}, 2},
+		{"electric", []string{"water", "flying"}, 4},
+		{"electric", []string{"electric"}, 0.5},
+		{"electric", []string{"ground", "flying"}, 0},
+		{"normal", []string{"water"}, 1},
+		{"ground", []string{"electric", "flying"}, 0},
+		{"water", []string{"water", "ground"}, 1},
+	}
+	for _, c := range cases {
+		multiplier, err := s.effectiveness(c.attacking, c.defending)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if multiplier != c.expected {
+			t.Errorf("%s against %v: expected x%g, got x%g", c.attacking, c.defending, c.expected, multiplier)
+		}
+	}
+}
+
+func TestImmuneMovesDoNoDamage(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	power, accuracy := 40, 100
+	thunderShock := pokeMove{Name: "thunder-shock", Power: &power, Accuracy: &accuracy, Type: pokeNamedResource{Name: "electric"}, DamageClass: pokeNamedResource{Name: "special"}}
+	attacker := &combatant{name: "pikachu", level: 10, spAttack: 20, types: []string{"electric"}}
+	defender := &combatant{name: "diglett", level: 10, hp: 20, spDefense: 20, types: []string{"ground"}}
+	for i := 0; i < 10; i++ {
+		turn, err := s.attack(attacker, defender, thunderShock)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !turn.Missed && (!turn.Immune || turn.Damage != 0) {
+			t.Errorf("expected a ground type to be immune, got %+v", turn)
+		}
+	}
+	if defender.hp != 20 {
+		t.Errorf("expected diglett to keep its hp, got %d", defender.hp)
+	}
+}
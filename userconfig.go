@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// userConfig is what gets written to config.json in the user config dir
+type userConfig struct {
+	Aliases map[string]string `json:"aliases"`
+	// Player is the command cries are piped into by cry --play
+	Player string `json:"player,omitempty"`
+}
+
+func defaultConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedexcli"), nil
+}
+
+// loadConfig reads the session's config file, a missing file is not an error
+func (s *session) loadConfig() error {
+	if s.configPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var cfg userConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	if cfg.Aliases != nil {
+		s.aliases = cfg.Aliases
+	}
+	s.player = cfg.Player
+	return nil
+}
+
+// saveConfig writes the session's config file, it is a no-op for sessions
+// without a config path
+func (s *session) saveConfig() error {
+	if s.configPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(userConfig{Aliases: s.aliases, Player: s.player}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.configPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.configPath, data, 0644)
+}
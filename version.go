@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// pokeNamedList is the shape of the api's unpaginated resource lists
+type pokeNamedList struct {
+	Count   int `json:"count"`
+	Results []struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"results"`
+}
+
+type pokeVersion struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	VersionGroup struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
+	} `json:"version_group"`
+}
+
+// listVersions fetches the names of every game version the api knows about
+func (c *pokeClient) listVersions() ([]string, error) {
+	var list pokeNamedList
+	if err := c.get(c.baseUrl+"/version/?limit=1000", &list); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, result := range list.Results {
+		names = append(names, result.Name)
+	}
+	return names, nil
+}
+
+func (c *pokeClient) getVersion(name string) (pokeVersion, error) {
+	var version pokeVersion
+	err := c.get(c.resourceUrl("version", name), &version)
+	return version, err
+}
+
+// areaPokemon lists the pokemon of an area, only those that can be met in
+// one game version when version is set
+func areaPokemon(area pokeLocationArea, version string) []string {
+	names := []string{}
+	for _, encounter := range area.PokemonEncounters {
+		found := version == ""
+		for _, versionDetail := range encounter.VersionDetails {
+			if versionDetail.Version.Name == version {
+				found = true
+			}
+		}
+		if found {
+			names = append(names, encounter.Pokemon.Name)
+		}
+	}
+	return names
+}
+
+type versionResult struct {
+	Version      string `json:"version"`
+	VersionGroup string `json:"version_group,omitempty"`
+}
+
+func (r versionResult) renderText(w io.Writer) {
+	if r.Version == "" {
+		fmt.Fprintf(w, "Version: %s\n", versionLabel(r.Version))
+		return
+	}
+	fmt.Fprintf(w, "Version: %s (%s)\n", r.Version, r.VersionGroup)
+}
+
+func (r versionResult) tableRows() ([]string, [][]string) {
+	return fieldRows("version", versionLabel(r.Version), "version group", r.VersionGroup)
+}
+
+type versionListResult struct {
+	Current  string   `json:"current"`
+	Versions []string `json:"versions"`
+}
+
+func (r versionListResult) renderText(w io.Writer) {
+	for _, name := range r.Versions {
+		marker := " "
+		if name == r.Current {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s %s\n", marker, name)
+	}
+}
+
+func (r versionListResult) pipeValues() []string {
+	return r.Versions
+}
+
+func (r versionListResult) tableRows() ([]string, [][]string) {
+	rows := [][]string{}
+	for _, name := range r.Versions {
+		current := ""
+		if name == r.Current {
+			current = "*"
+		}
+		rows = append(rows, []string{name, current})
+	}
+	return []string{"VERSION", "CURRENT"}, rows
+}
+
+// setVersion limits the session to one game version, checking the name
+// against the api's version list. an empty name lifts the limit
+func (s *session) setVersion(name string) error {
+	if name == "" {
+		s.version = ""
+		s.versionGroup = ""
+		return nil
+	}
+	names, err := s.client.listVersions()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(names, name) {
+		return fmt.Errorf("unknown game version %q (see version list)", name)
+	}
+	version, err := s.client.getVersion(name)
+	if err != nil {
+		return err
+	}
+	s.version = version.Name
+	s.versionGroup = version.VersionGroup.Name
+	return nil
+}
+
+func commandVersion(s *session, params ...string) (commandResult, error) {
+	if len(params) > 1 {
+		return nil, fmt.Errorf("version command takes at most one parameter")
+	}
+	if len(params) == 0 {
+		return versionResult{Version: s.version, VersionGroup: s.versionGroup}, nil
+	}
+	switch params[0] {
+	case "list":
+		names, err := s.client.listVersions()
+		if err != nil {
+			return nil, err
+		}
+		return versionListResult{Current: s.version, Versions: names}, nil
+	case "all":
+		s.setVersion("")
+		return messageResult{Message: "no longer limited to a game version"}, nil
+	}
+	if err := s.setVersion(params[0]); err != nil {
+		return nil, err
+	}
+	return messageResult{Message: fmt.Sprintf("version set to %s (%s)", s.version, s.versionGroup)}, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersionCommand(t *testing.T) {
+	s, out, errOut := newTestSession(t)
+	lines := []string{
+		"version platinum",
+		"explore canalave-city-area",
+		"version nonsense",
+		"version",
+	}
+	for _, line := range lines {
+		if err := s.runLine(line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if s.version != "platinum" || s.versionGroup != "platinum" {
+		t.Errorf("expected platinum to stay selected, got %q (%q)", s.version, s.versionGroup)
+	}
+	for _, expected := range []string{"version set to platinum (platinum)", "\ntentacool\nmagikarp\n\n", "Version: platinum (platinum)"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q, got %q", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "wingull") {
+		t.Errorf("expected wingull to be left out of platinum, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), `unknown game version "nonsense"`) {
+		t.Errorf("expected an unknown version error, got %q", errOut.String())
+	}
+
+	if err := s.runLine("version all"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.version != "" || s.versionGroup != "" {
+		t.Errorf("expected the version to be cleared, got %q (%q)", s.version, s.versionGroup)
+	}
+}
+
+func TestVersionIsSaved(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	if err := s.setVersion("diamond"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state := s.snapshot()
+
+	restored, _, _ := newTestSession(t)
+	restored.restore(state)
+	if restored.version != "diamond" || restored.versionGroup != "diamond-pearl" {
+		t.Errorf("expected diamond (diamond-pearl), got %q (%q)", restored.version, restored.versionGroup)
+	}
+}
+
+func TestLearnset(t *testing.T) {
+	s, _, _ := newTestSession(t)
+	pokemon, err := s.client.getPokemon("pikachu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group := latestVersionGroup(pokemon); group != "diamond-pearl" {
+		t.Errorf("expected diamond-pearl to be the newest group, got %q", group)
+	}
+
+	expected := []learnsetMove{
+		{Name: "thunder-shock", Method: "level-up", Level: 1},
+		{Name: "quick-attack", Method: "level-up", Level: 16},
+		{Name: "thunderbolt", Method: "machine"},
+	}
+	if moves := learnset(pokemon, "red-blue", ""); !reflect.DeepEqual(moves, expected) {
+		t.Errorf("expected %v, got %v", expected, moves)
+	}
+	if moves := learnset(pokemon, "red-blue", "machine"); !reflect.DeepEqual(moves, expected[2:]) {
+		t.Errorf("expected %v, got %v", expected[2:], moves)
+	}
+}